@@ -0,0 +1,371 @@
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	corev1 "github.com/eiladin/k8s-dotenv/pkg/client/core/v1"
+	"github.com/eiladin/k8s-dotenv/pkg/dotenv"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/spf13/cobra"
+)
+
+// ErrResourceNameRequired is returned when no resource name is provided.
+var ErrResourceNameRequired = errors.New("resource name required")
+
+// ErrUnknownSource is returned when the file has a section for a ConfigMap or Secret the workload does not use.
+var ErrUnknownSource = errors.New("section is not a source of the workload")
+
+// ErrTransformedFile is returned when the file was written with values that differ from the cluster, such as
+// redacted or filtered values.
+var ErrTransformedFile = errors.New("file values were transformed when it was written")
+
+// ErrAmbiguousKey is returned when a key of the file matches several keys of a source, such as `app.name` and
+// `appname` which are both written as `appname`.
+var ErrAmbiguousKey = errors.New("key matches several keys of the source")
+
+func runError(err error) error {
+	return fmt.Errorf("apply error: %w", err)
+}
+
+// applyOptions configures what `apply` changes.
+type applyOptions struct {
+	file    string
+	dryRun  bool
+	restart bool
+	add     bool
+	prune   bool
+}
+
+// update is a ConfigMap or Secret with the changes made to it in the local file.
+type update struct {
+	kind    string
+	patch   *corev1.SourcePatch
+	changes []result.Change
+}
+
+// NewCmd creates the `apply` command.
+func NewCmd(opt *options.CLI) *cobra.Command {
+	var applyOpt applyOptions
+
+	cmd := &cobra.Command{
+		Use:   "apply RESOURCE_TYPE/RESOURCE_NAME",
+		Short: "update the ConfigMaps and Secrets of a workload from a local .env file",
+		Long: `Update the ConfigMaps and Secrets of a workload with the values of the matching sections of a local .env file,
+such as "##### CONFIGMAP - name #####". When the file has managed blocks only the block of the workload is read.
+
+Keys are matched the way they are written to .env files, so "app.name" in a source is updated from "appname" in
+the file. Only keys found in both are updated: keys only found in the file are added with --add and keys missing
+from the file are deleted with --prune.
+
+Files written with --redact, --include, --exclude, --rename, --set, --unset, --expand or --secret-types are
+refused since their values differ from the cluster.
+
+The changes are printed first, secret values are redacted unless --redact=false is given. Sources are only
+updated when they were not changed since they were read. Literal values set in the workload are not applied.`,
+		Example: `  k8s-dotenv apply deploy/api --dry-run
+  k8s-dotenv apply deploy/api --file .env.local --restart
+  k8s-dotenv apply deploy/api --add --prune`,
+		Annotations: map[string]string{options.NoOutput: "true"},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return client.WorkloadTypes(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if !c.Flags().Changed("redact") {
+				opt.Output.Redact = true
+			}
+
+			return run(opt, applyOpt, args)
+		},
+	}
+
+	cmd.Flags().StringVarP(&applyOpt.file, "file", "f", ".env", "Local file to apply")
+	cmd.Flags().BoolVar(&applyOpt.dryRun, "dry-run", false, "Only print the changes")
+	cmd.Flags().BoolVar(&applyOpt.restart, "restart", false,
+		"Restart the workload after updating its sources, like kubectl rollout restart")
+	cmd.Flags().BoolVar(&applyOpt.add, "add", false, "Add keys of the file that are not in their source")
+	cmd.Flags().BoolVar(&applyOpt.prune, "prune", false, "Delete keys of a source that are missing from its section")
+
+	return cmd
+}
+
+// readFile parses the managed block written for resource in the file, or the whole file when it has no blocks.
+func readFile(name, resource string) (*dotenv.File, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	block, err := result.BlockContent(string(content), resource)
+	if err != nil {
+		//nolint
+		return nil, err
+	}
+
+	//nolint
+	return dotenv.Read(strings.NewReader(block))
+}
+
+// sectionValues merges the values of the sections for each source, later lines win.
+func sectionValues(file *dotenv.File) (map[string]map[string]string, []string) {
+	values := map[string]map[string]string{}
+	order := []string{}
+
+	for _, section := range file.Sections {
+		id := section.Kind + "/" + section.Name
+		if _, found := values[id]; !found {
+			values[id] = map[string]string{}
+			order = append(order, id)
+		}
+
+		for _, entry := range section.Entries {
+			values[id][entry.Key] = entry.Value
+		}
+	}
+
+	return values, order
+}
+
+// plan returns the sources changed in the file and notes about the changes it does not make, sources that did not
+// change are skipped.
+func plan(kubeClient *client.Client, cluster *result.Result, file *dotenv.File, applyOpt applyOptions) ([]update, []string, error) {
+	values, order := sectionValues(file)
+	updates := []update{}
+	notes := []string{}
+
+	for _, id := range order {
+		kind, name, _ := strings.Cut(id, "/")
+		local := values[id]
+
+		var (
+			source *corev1.Source
+			err    error
+			found  bool
+		)
+
+		switch kind {
+		case result.KindConfigMap:
+			if _, found = cluster.ConfigMaps[name]; found {
+				source, err = kubeClient.CoreV1().ConfigMapSource(name)
+			}
+		case result.KindSecret:
+			if _, found = cluster.Secrets[name]; found {
+				source, err = kubeClient.CoreV1().SecretSource(name)
+			}
+		default:
+			environment := (&result.Result{Environment: cluster.Environment}).DotenvKeys()
+			if len(result.Diff(environment, &result.Result{Environment: local})) > 0 {
+				notes = append(notes, "environment: literal values are set in the workload and are not applied")
+			}
+
+			continue
+		}
+
+		if !found {
+			return nil, nil, fmt.Errorf("%w: %s %s", ErrUnknownSource, strings.ToLower(kind), name)
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sourceUpdate, sourceNotes, err := patch(kind, source, local, applyOpt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		notes = append(notes, sourceNotes...)
+
+		if len(sourceUpdate.changes) > 0 {
+			updates = append(updates, *sourceUpdate)
+		}
+	}
+
+	return updates, notes, nil
+}
+
+// patch compares the keys of a source with the values of its section, matching them the way keys are written to
+// .env files. Keys only found on one side are added or deleted when applyOpt allows it, otherwise they are noted.
+func patch(kind string, source *corev1.Source, local map[string]string, applyOpt applyOptions) (*update, []string, error) {
+	label := strings.ToLower(kind) + " " + source.Name
+	res := &update{kind: kind, patch: &corev1.SourcePatch{
+		Name:            source.Name,
+		Set:             map[string]string{},
+		ResourceVersion: source.ResourceVersion,
+	}}
+
+	clusterKeys := map[string][]string{}
+	for _, key := range sortedKeys(source.Data) {
+		clusterKeys[parser.Key(key)] = append(clusterKeys[parser.Key(key)], key)
+	}
+
+	added := []string{}
+
+	for _, key := range sortedKeys(local) {
+		matched := clusterKeys[key]
+
+		switch {
+		case len(matched) > 1:
+			return nil, nil, fmt.Errorf("%w: %s in %s matches %s", ErrAmbiguousKey, key, label, strings.Join(matched, ", "))
+		case len(matched) == 1 && source.Data[matched[0]] != local[key]:
+			res.patch.Set[matched[0]] = local[key]
+			res.changes = append(res.changes, result.Change{
+				Type: result.ChangeModified, Key: matched[0], Old: source.Data[matched[0]], New: local[key],
+			})
+		case len(matched) == 0 && applyOpt.add:
+			res.patch.Set[key] = local[key]
+			res.changes = append(res.changes, result.Change{Type: result.ChangeAdded, Key: key, New: local[key]})
+		case len(matched) == 0:
+			added = append(added, key)
+		}
+	}
+
+	kept := []string{}
+
+	for _, key := range sortedKeys(source.Data) {
+		if _, found := local[parser.Key(key)]; found {
+			continue
+		}
+
+		if !applyOpt.prune {
+			kept = append(kept, key)
+
+			continue
+		}
+
+		res.patch.Delete = append(res.patch.Delete, key)
+		res.changes = append(res.changes, result.Change{Type: result.ChangeRemoved, Key: key, Old: source.Data[key]})
+	}
+
+	for i := range res.changes {
+		res.changes[i].Secret = kind == result.KindSecret
+	}
+
+	sort.SliceStable(res.changes, func(i, j int) bool { return res.changes[i].Key < res.changes[j].Key })
+
+	notes := []string{}
+
+	if len(added) > 0 {
+		notes = append(notes, fmt.Sprintf("%s: %s not in the source, use --add to add them", label, strings.Join(added, ", ")))
+	}
+
+	if len(kept) > 0 {
+		notes = append(notes, fmt.Sprintf("%s: %s missing from the file, use --prune to delete them", label, strings.Join(kept, ", ")))
+	}
+
+	return res, notes, nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func run(opt *options.CLI, applyOpt applyOptions, args []string) error {
+	if len(args) == 0 {
+		return ErrResourceNameRequired
+	}
+
+	resourceType, name, err := client.ParseResource(args[0])
+	if err != nil {
+		return runError(err)
+	}
+
+	if applyOpt.restart && !client.CanRestart(resourceType) {
+		return runError(fmt.Errorf("%w: %s cannot be restarted", client.ErrUnsupportedType, resourceType))
+	}
+
+	file, err := readFile(applyOpt.file, client.ResourceName(resourceType, name))
+	if err != nil {
+		return runError(err)
+	}
+
+	if len(file.Transforms) > 0 {
+		return runError(fmt.Errorf("%w: %s is %s, write it again without these options",
+			ErrTransformedFile, applyOpt.file, strings.Join(file.Transforms, ", ")))
+	}
+
+	kubeClient := client.NewClient(
+		client.WithKubeClient(opt.KubeClient),
+		client.WithNamespace(opt.Namespace),
+		client.WithContainer(opt.Container),
+	)
+
+	cluster := kubeClient.Workload(resourceType, name)
+	if cluster.Error != nil {
+		return runError(cluster.Error)
+	}
+
+	updates, notes, err := plan(kubeClient, cluster, file, applyOpt)
+	if err != nil {
+		return runError(err)
+	}
+
+	var res strings.Builder
+
+	for _, note := range notes {
+		fmt.Fprintf(&res, "# %s\n", note)
+	}
+
+	for _, update := range updates {
+		fmt.Fprintf(&res, "##### %s - %s #####\n", update.kind, update.patch.Name)
+
+		if err := result.WriteChanges(&res, update.changes, opt.Output); err != nil {
+			return runError(err)
+		}
+	}
+
+	if !applyOpt.dryRun {
+		err = apply(kubeClient, updates, &res)
+
+		if err == nil && applyOpt.restart && len(updates) > 0 {
+			if err = kubeClient.Restart(resourceType, name, time.Now()); err == nil {
+				fmt.Fprintf(&res, "%s restarted\n", args[0])
+			}
+		}
+	}
+
+	if _, writeErr := opt.Writer.Write([]byte(res.String())); writeErr != nil && err == nil {
+		err = writeErr
+	}
+
+	if err != nil {
+		return runError(err)
+	}
+
+	return nil
+}
+
+// apply updates the sources and logs each update to log.
+func apply(kubeClient *client.Client, updates []update, log *strings.Builder) error {
+	for _, update := range updates {
+		var err error
+		if update.kind == result.KindSecret {
+			err = kubeClient.CoreV1().UpdateSecret(update.patch)
+		} else {
+			err = kubeClient.CoreV1().UpdateConfigMap(update.patch)
+		}
+
+		if err != nil {
+			//nolint
+			return err
+		}
+
+		fmt.Fprintf(log, "%s %s updated\n", strings.ToLower(update.kind), update.patch.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,338 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	appsv1 "github.com/eiladin/k8s-dotenv/pkg/client/apps/v1"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func newKubeClient() *mock.FakeClient {
+	return mock.NewFakeClient(
+		mock.Deployment("test", "test", map[string]string{"literal": "v"}, []string{"cm"}, []string{"sec"}),
+		mock.ConfigMap("cm", "test", map[string]string{"a": "1", "b": "2"}),
+		mock.Secret("sec", "test", map[string][]byte{"token": []byte("cluster")}),
+	)
+}
+
+func TestNewCmd(t *testing.T) {
+	kubeClient := newKubeClient()
+
+	t.Run("create", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		if got == nil {
+			t.Errorf("NewCmd() is nil want not nil")
+		}
+	})
+
+	t.Run("valid args", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		resources, _ := got.ValidArgsFunction(got, []string{}, "")
+		if len(resources) == 0 {
+			t.Errorf("NewCmd().ValidArgs = %v, want resource types", resources)
+		}
+	})
+
+	t.Run("runE", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		err := got.RunE(got, []string{})
+		if !errors.Is(err, ErrResourceNameRequired) {
+			t.Errorf("NewCmd().RunE = %v, want %v", err, ErrResourceNameRequired)
+		}
+	})
+
+	t.Run("redact by default", func(t *testing.T) {
+		opt := &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: mock.NewWriter()}
+		got := NewCmd(opt)
+		_ = got.Flags().Set("file", writeFile(t, "literal=v\n"))
+		_ = got.Flags().Set("dry-run", "true")
+
+		if err := got.RunE(got, []string{"deploy/test"}); err != nil {
+			t.Errorf("NewCmd().RunE = %v, want nil", err)
+		}
+
+		if !opt.Output.Redact {
+			t.Errorf("NewCmd().RunE redact = false, want true")
+		}
+	})
+}
+
+func Test_runError(t *testing.T) {
+	type args struct {
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{name: "wraps error", args: args{err: mock.AnError}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runError(tt.args.err); (err != nil) != tt.wantErr {
+				t.Errorf("runError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	same := writeFile(t, "literal=v\n##### CONFIGMAP - cm #####\na=1\nb=2\n##### SECRET - sec #####\ntoken=cluster\n")
+	changed := writeFile(t, "literal=changed\n##### CONFIGMAP - cm #####\na=10\nc=3\n##### SECRET - sec #####\ntoken=local\n")
+	unknown := writeFile(t, "##### CONFIGMAP - other #####\na=1\n")
+	invalid := writeFile(t, "not a dotenv line\n")
+	filtered := writeFile(t, "# k8s-dotenv: filtered\n##### CONFIGMAP - cm #####\na=10\n")
+	redacted := writeFile(t, "# k8s-dotenv: redacted\n##### SECRET - sec #####\ntoken=\"********\"\n")
+	other := "# BEGIN k8s-dotenv deployment/other\n# k8s-dotenv: redacted\n##### CONFIGMAP - other #####\nx=1\n" +
+		"# END k8s-dotenv deployment/other\n"
+	otherBlock := writeFile(t, other)
+	blocks := writeFile(t, other+"# BEGIN k8s-dotenv deployment/test\nliteral=changed\n##### CONFIGMAP - cm #####\na=10\nc=3\n"+
+		"##### SECRET - sec #####\ntoken=local\n# END k8s-dotenv deployment/test\n")
+
+	changes := "# environment: literal values are set in the workload and are not applied\n" +
+		"# configmap cm: c not in the source, use --add to add them\n" +
+		"# configmap cm: b missing from the file, use --prune to delete them\n" +
+		"##### CONFIGMAP - cm #####\n~ a=1 -> 10\n##### SECRET - sec #####\n~ token=cluster -> local\n"
+	patched := map[string]string{"a": "10", "b": "2"}
+
+	type args struct {
+		applyOpt applyOptions
+		args     []string
+	}
+
+	tests := []struct {
+		name        string
+		args        args
+		writer      func() *mock.Writer
+		want        string
+		wantErr     bool
+		wantErrIs   error
+		wantApplied bool
+		wantData    map[string]string
+	}{
+		{name: "error with no args", wantErr: true, wantErrIs: ErrResourceNameRequired},
+		{name: "error with invalid resource", args: args{applyOpt: applyOptions{file: same}, args: []string{"test"}}, wantErr: true},
+		{
+			name:    "error with missing file",
+			args:    args{applyOpt: applyOptions{file: filepath.Join(t.TempDir(), "missing")}, args: []string{"deploy/test"}},
+			wantErr: true,
+		},
+		{name: "error with invalid file", args: args{applyOpt: applyOptions{file: invalid}, args: []string{"deploy/test"}}, wantErr: true},
+		{name: "error with missing workload", args: args{applyOpt: applyOptions{file: same}, args: []string{"deploy/missing"}}, wantErr: true},
+		{
+			name:    "error with unknown source",
+			args:    args{applyOpt: applyOptions{file: unknown}, args: []string{"deploy/test"}},
+			wantErr: true, wantErrIs: ErrUnknownSource,
+		},
+		{
+			name:    "error with filtered file",
+			args:    args{applyOpt: applyOptions{file: filtered}, args: []string{"deploy/test"}},
+			wantErr: true, wantErrIs: ErrTransformedFile,
+		},
+		{
+			name:    "error with redacted file",
+			args:    args{applyOpt: applyOptions{file: redacted}, args: []string{"deploy/test"}},
+			wantErr: true, wantErrIs: ErrTransformedFile,
+		},
+		{
+			name:    "error without a block for the workload",
+			args:    args{applyOpt: applyOptions{file: otherBlock}, args: []string{"deploy/test"}},
+			wantErr: true, wantErrIs: result.ErrMissingBlock,
+		},
+		{
+			name:    "error restarting a pod",
+			args:    args{applyOpt: applyOptions{file: same, restart: true}, args: []string{"pod/test"}},
+			wantErr: true,
+		},
+		{name: "no changes", args: args{applyOpt: applyOptions{file: same, restart: true}, args: []string{"deploy/test"}}},
+		{
+			name: "dry run",
+			args: args{applyOpt: applyOptions{file: changed, dryRun: true}, args: []string{"deploy/test"}},
+			want: changes,
+		},
+		{
+			name:        "apply",
+			args:        args{applyOpt: applyOptions{file: changed}, args: []string{"deploy/test"}},
+			want:        changes + "configmap cm updated\nsecret sec updated\n",
+			wantApplied: true,
+			wantData:    patched,
+		},
+		{
+			name:        "apply and restart",
+			args:        args{applyOpt: applyOptions{file: changed, restart: true}, args: []string{"deploy/test"}},
+			want:        changes + "configmap cm updated\nsecret sec updated\ndeploy/test restarted\n",
+			wantApplied: true,
+			wantData:    patched,
+		},
+		{
+			name:        "apply the block of the workload",
+			args:        args{applyOpt: applyOptions{file: blocks}, args: []string{"deploy/test"}},
+			want:        changes + "configmap cm updated\nsecret sec updated\n",
+			wantApplied: true,
+			wantData:    patched,
+		},
+		{
+			name: "apply with add and prune",
+			args: args{applyOpt: applyOptions{file: changed, add: true, prune: true}, args: []string{"deploy/test"}},
+			want: "# environment: literal values are set in the workload and are not applied\n" +
+				"##### CONFIGMAP - cm #####\n~ a=1 -> 10\n- b=2\n+ c=3\n##### SECRET - sec #####\n~ token=cluster -> local\n" +
+				"configmap cm updated\nsecret sec updated\n",
+			wantApplied: true,
+			wantData:    map[string]string{"a": "10", "c": "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := newKubeClient()
+			writer := mock.NewWriter()
+			opt := &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: writer}
+
+			err := run(opt, tt.args.applyOpt, tt.args.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("run() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if got := writer.String(); got != tt.want {
+				t.Errorf("run() = %q, want %q", got, tt.want)
+			}
+
+			wantData := tt.wantData
+			if wantData == nil {
+				wantData = map[string]string{"a": "1", "b": "2"}
+			}
+
+			configMap, _ := kubeClient.CoreV1().ConfigMaps("test").Get(context.TODO(), "cm", metav1.GetOptions{})
+			if !cmp.Equal(configMap.Data, wantData) {
+				t.Errorf("run() data = %v", cmp.Diff(wantData, configMap.Data))
+			}
+
+			deployment, _ := kubeClient.AppsV1().Deployments("test").Get(context.TODO(), "test", metav1.GetOptions{})
+			_, restarted := deployment.Spec.Template.Annotations[appsv1.RestartedAtAnnotation]
+			if restarted != (tt.args.applyOpt.restart && tt.wantApplied) {
+				t.Errorf("run() restarted = %v", restarted)
+			}
+		})
+	}
+}
+
+func Test_run_writeError(t *testing.T) {
+	file := writeFile(t, "##### CONFIGMAP - cm #####\na=2\n")
+	opt := &options.CLI{KubeClient: newKubeClient(), Namespace: "test", Writer: mock.NewErrorWriter().ErrorAfter(0)}
+
+	if err := run(opt, applyOptions{file: file}, []string{"deploy/test"}); err == nil {
+		t.Errorf("run() error = nil, want error")
+	}
+}
+
+func Test_run_updateError(t *testing.T) {
+	file := writeFile(t, "##### CONFIGMAP - cm #####\na=2\n")
+	writer := mock.NewWriter()
+	kubeClient := newKubeClient().PrependReactor("update", "configmaps", true, nil, mock.AnError)
+	opt := &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: writer}
+
+	if err := run(opt, applyOptions{file: file}, []string{"deploy/test"}); !errors.Is(err, mock.AnError) {
+		t.Errorf("run() error = %v, want %v", err, mock.AnError)
+	}
+
+	want := "# configmap cm: b missing from the file, use --prune to delete them\n##### CONFIGMAP - cm #####\n~ a=1 -> 2\n"
+	if writer.String() != want {
+		t.Errorf("run() = %q, want %q", writer.String(), want)
+	}
+}
+
+func Test_run_dotenvKeys(t *testing.T) {
+	cluster := map[string]string{"app.name": "api", "pattern": `^\d+\\$`, "quote": `say "hi" # not`}
+
+	tests := []struct {
+		name      string
+		cluster   map[string]string
+		local     map[string]string
+		want      string
+		wantErrIs error
+		wantData  map[string]string
+	}{
+		{name: "no changes", cluster: cluster, local: cluster, wantData: cluster},
+		{
+			name:     "dotted key",
+			cluster:  cluster,
+			local:    map[string]string{"app.name": "web", "pattern": `^\d+\\$`, "quote": `say "hi" # not`},
+			want:     "##### CONFIGMAP - cm #####\n~ app.name=api -> web\nconfigmap cm updated\n",
+			wantData: map[string]string{"app.name": "web", "pattern": `^\d+\\$`, "quote": `say "hi" # not`},
+		},
+		{
+			name:    "backslashes and quotes",
+			cluster: cluster,
+			local:   map[string]string{"app.name": "api", "pattern": `^\w+\\$`, "quote": `say "bye"`},
+			want: "##### CONFIGMAP - cm #####\n~ pattern=^\\d+\\\\$ -> ^\\w+\\\\$\n~ quote=say \"hi\" # not -> say \"bye\"\n" +
+				"configmap cm updated\n",
+			wantData: map[string]string{"app.name": "api", "pattern": `^\w+\\$`, "quote": `say "bye"`},
+		},
+		{
+			name:      "error with ambiguous key",
+			cluster:   map[string]string{"app.name": "a", "appname": "b"},
+			local:     map[string]string{"appname": "c"},
+			wantErrIs: ErrAmbiguousKey,
+			wantData:  map[string]string{"app.name": "a", "appname": "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := mock.NewFakeClient(
+				mock.Deployment("test", "test", nil, []string{"cm"}, nil),
+				mock.ConfigMap("cm", "test", tt.cluster),
+			)
+
+			var content bytes.Buffer
+			local := &result.Result{ConfigMaps: map[string]result.EnvValues{"cm": tt.local}}
+			if err := local.Write(&content); err != nil {
+				t.Fatalf("Result.Write() error = %v", err)
+			}
+
+			writer := mock.NewWriter()
+			opt := &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: writer}
+
+			err := run(opt, applyOptions{file: writeFile(t, content.String())}, []string{"deploy/test"})
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("run() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if got := writer.String(); got != tt.want {
+				t.Errorf("run() = %q, want %q", got, tt.want)
+			}
+
+			configMap, _ := kubeClient.CoreV1().ConfigMaps("test").Get(context.TODO(), "cm", metav1.GetOptions{})
+			if !cmp.Equal(configMap.Data, tt.wantData) {
+				t.Errorf("run() data = %v", cmp.Diff(tt.wantData, configMap.Data))
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/kubeclient"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/spf13/cobra"
+)
+
+// ErrResourceNameRequired is returned when no resource name is provided.
+var ErrResourceNameRequired = errors.New("resource name required")
+
+// ErrAgainstRequired is returned when neither --against-context nor --against-namespace is provided.
+var ErrAgainstRequired = errors.New("--against-context or --against-namespace required")
+
+// driftExitCode is the exit code when the environments differ, like diff(1).
+const driftExitCode = 1
+
+func runError(err error) error {
+	return fmt.Errorf("compare error: %w", err)
+}
+
+// NewCmd creates the `compare` command.
+func NewCmd(opt *options.CLI) *cobra.Command {
+	var againstContext, againstNamespace string
+
+	cmd := &cobra.Command{
+		Use:   "compare RESOURCE_TYPE/RESOURCE_NAME",
+		Short: "compare the environment of a workload in two contexts or namespaces",
+		Long: `Compare the environment of a workload in two contexts or namespaces and print the keys that were added (+),
+removed (-) or modified (~) in the second one. Both are resolved concurrently.
+Secret values are only shown as a prefix of their SHA-256 hash. The exit code is 1 when they differ.`,
+		Example: `  k8s-dotenv compare deploy/api --context staging --against-context prod
+  k8s-dotenv compare deploy/api -n team-a --against-namespace team-b`,
+		Annotations: map[string]string{options.NoOutput: "true"},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return client.WorkloadTypes(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if againstContext == "" && againstNamespace == "" {
+				return ErrAgainstRequired
+			}
+
+			against, err := resolveAgainst(opt, againstContext, againstNamespace)
+			if err != nil {
+				return runError(err)
+			}
+
+			err = run(opt, against, args)
+
+			var exitErr *process.ExitError
+			if errors.As(err, &exitErr) {
+				c.SilenceErrors = true
+				c.SilenceUsage = true
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&againstContext, "against-context", "", "Kubeconfig context to compare with (default --context)")
+	cmd.Flags().StringVar(&againstNamespace, "against-namespace", "",
+		"Namespace to compare with (default the namespace of --against-context, or --namespace)")
+
+	return cmd
+}
+
+// resolveAgainst returns the options used to resolve the workload it is compared with.
+func resolveAgainst(opt *options.CLI, context, namespace string) (*options.CLI, error) {
+	against := *opt
+
+	if namespace != "" {
+		against.Namespace = namespace
+	}
+
+	if context != "" {
+		kubeClient, err := kubeclient.ForContext(context)
+		if err != nil {
+			//nolint
+			return nil, err
+		}
+
+		against.KubeClient = kubeClient
+		against.Context = context
+
+		if namespace == "" {
+			if err := against.ResolveNamespace(); err != nil {
+				//nolint
+				return nil, err
+			}
+		}
+	}
+
+	return &against, nil
+}
+
+// label describes where a workload is resolved.
+func label(opt *options.CLI, resource string) string {
+	context := opt.Context
+	if context == "" {
+		context = "current-context"
+	}
+
+	return fmt.Sprintf("%s %s/%s", resource, context, opt.Namespace)
+}
+
+func workload(opt *options.CLI, resourceType, name string) *result.Result {
+	return client.NewClient(
+		client.WithKubeClient(opt.KubeClient),
+		client.WithNamespace(opt.Namespace),
+		client.WithContainer(opt.Container),
+		client.WithOutput(opt.Output),
+	).Workload(resourceType, name)
+}
+
+func run(opt *options.CLI, against *options.CLI, args []string) error {
+	if len(args) == 0 {
+		return ErrResourceNameRequired
+	}
+
+	resourceType, name, err := client.ParseResource(args[0])
+	if err != nil {
+		return runError(err)
+	}
+
+	var from, to *result.Result
+
+	var wg sync.WaitGroup
+
+	wg.Add(2) //nolint
+
+	go func() {
+		defer wg.Done()
+
+		from = workload(opt, resourceType, name)
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		to = workload(against, resourceType, name)
+	}()
+
+	wg.Wait()
+
+	for _, res := range []*result.Result{from, to} {
+		if res.Error != nil {
+			return runError(res.Error)
+		}
+	}
+
+	changes := result.Diff(from, to)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(opt.Writer, "--- %s\n+++ %s\n", label(opt, args[0]), label(against, args[0])); err != nil {
+		return runError(err)
+	}
+
+	output := options.Output{Redact: true, RedactStyle: result.RedactHash}
+	if err := result.WriteChanges(opt.Writer, changes, output); err != nil {
+		return runError(err)
+	}
+
+	return &process.ExitError{Code: driftExitCode}
+}
@@ -0,0 +1,178 @@
+package compare
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+)
+
+func TestNewCmd(t *testing.T) {
+	kubeClient := mock.NewFakeClient(mock.Pod("test", "test", nil, nil, nil))
+
+	t.Run("create", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		if got == nil {
+			t.Errorf("NewCmd() is nil want not nil")
+		}
+	})
+
+	t.Run("valid args", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		resources, _ := got.ValidArgsFunction(got, []string{}, "")
+		if len(resources) == 0 {
+			t.Errorf("NewCmd().ValidArgs = %v, want resource types", resources)
+		}
+	})
+
+	t.Run("runE without against", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		err := got.RunE(got, []string{"pod/test"})
+		if !errors.Is(err, ErrAgainstRequired) {
+			t.Errorf("NewCmd().RunE = %v, want %v", err, ErrAgainstRequired)
+		}
+	})
+
+	t.Run("runE", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		_ = got.Flags().Set("against-namespace", "other")
+		err := got.RunE(got, []string{})
+		if !errors.Is(err, ErrResourceNameRequired) {
+			t.Errorf("NewCmd().RunE = %v, want %v", err, ErrResourceNameRequired)
+		}
+	})
+}
+
+func Test_runError(t *testing.T) {
+	type args struct {
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{name: "wraps error", args: args{err: mock.AnError}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runError(tt.args.err); (err != nil) != tt.wantErr {
+				t.Errorf("runError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_resolveAgainst(t *testing.T) {
+	opt := &options.CLI{Context: "staging", Namespace: "test", Container: "api"}
+
+	got, err := resolveAgainst(opt, "", "other")
+	if err != nil {
+		t.Fatalf("resolveAgainst() error = %v", err)
+	}
+
+	if got.Namespace != "other" || got.Context != "staging" || got.Container != "api" {
+		t.Errorf("resolveAgainst() = %+v", got)
+	}
+
+	if opt.Namespace != "test" {
+		t.Errorf("resolveAgainst() changed the options to %+v", opt)
+	}
+}
+
+func Test_run(t *testing.T) {
+	staging := mock.NewFakeClient(
+		mock.Pod("test", "test", map[string]string{"same": "v", "url": "staging"}, nil, []string{"sec"}),
+		mock.Secret("sec", "test", map[string][]byte{"password": []byte("secret")}),
+	)
+	prod := mock.NewFakeClient(
+		mock.Pod("test", "prod", map[string]string{"same": "v", "url": "prod"}, nil, []string{"sec"}),
+		mock.Secret("sec", "prod", map[string][]byte{"password": []byte("other")}),
+	)
+
+	type args struct {
+		opt     *options.CLI
+		against *options.CLI
+		args    []string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		want     string
+		wantErr  bool
+		wantCode int
+	}{
+		{name: "error with no args", args: args{opt: &options.CLI{}, against: &options.CLI{}}, wantErr: true},
+		{
+			name: "error with invalid resource",
+			args: args{
+				opt:     &options.CLI{KubeClient: staging, Namespace: "test"},
+				against: &options.CLI{KubeClient: prod, Namespace: "prod"},
+				args:    []string{"test"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error with missing pod",
+			args: args{
+				opt:     &options.CLI{KubeClient: staging, Namespace: "test"},
+				against: &options.CLI{KubeClient: prod, Namespace: "missing"},
+				args:    []string{"pod/test"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "same environment",
+			args: args{
+				opt:     &options.CLI{KubeClient: staging, Namespace: "test"},
+				against: &options.CLI{KubeClient: staging, Namespace: "test"},
+				args:    []string{"pod/test"},
+			},
+		},
+		{
+			name: "different environment",
+			args: args{
+				opt:     &options.CLI{KubeClient: staging, Context: "staging", Namespace: "test"},
+				against: &options.CLI{KubeClient: prod, Context: "prod", Namespace: "prod"},
+				args:    []string{"pod/test"},
+			},
+			want: "--- pod/test staging/test\n+++ pod/test prod/prod\n" +
+				"~ password=********(sha256:2bb80d53) -> ********(sha256:d9298a10)\n" +
+				"~ url=staging -> prod\n",
+			wantCode: driftExitCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := mock.NewWriter()
+			tt.args.opt.Writer = writer
+
+			err := run(tt.args.opt, tt.args.against, tt.args.args)
+
+			code := 0
+
+			var exitErr *process.ExitError
+			if errors.As(err, &exitErr) {
+				code, err = exitErr.Code, nil
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if code != tt.wantCode {
+				t.Errorf("run() exit code = %d, want %d", code, tt.wantCode)
+			}
+
+			if got := writer.String(); got != tt.want {
+				t.Errorf("run() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package decrypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/eiladin/k8s-dotenv/pkg/dotenv"
+	"github.com/eiladin/k8s-dotenv/pkg/encryption"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// ErrFilenameRequired is returned when no file is provided.
+var ErrFilenameRequired = errors.New("file required")
+
+func runError(err error) error {
+	return fmt.Errorf("decrypt error: %w", err)
+}
+
+// NewCmd creates the `decrypt` command.
+func NewCmd(opt *options.CLI) *cobra.Command {
+	var identities []string
+
+	cmd := &cobra.Command{
+		Use:   "decrypt FILE [-- COMMAND [ARGS...]]",
+		Short: "decrypt a file written with --encrypt-to, or run a command with its environment",
+		Long: `Decrypt a file written with --encrypt-to and print it, or run a command with the values of the file
+merged over the current environment so they are never written to disk in plaintext.
+
+Identities are age identity files or SSH private keys, the default is $K8S_DOTENV_IDENTITY
+or ~/.ssh/id_ed25519 and ~/.ssh/id_rsa.`,
+		Example: `  k8s-dotenv decrypt .env.age
+  k8s-dotenv decrypt .env.age -i ~/.config/age/keys.txt -- go run ./cmd/api`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			opt.Writer = cmd.OutOrStdout()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			err := run(opt, identities, args, c.ArgsLenAtDash())
+
+			var exitErr *process.ExitError
+			if errors.As(err, &exitErr) {
+				c.SilenceErrors = true
+				c.SilenceUsage = true
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&identities, "identity", "i", nil, "Identity file used to decrypt, can be repeated")
+
+	return cmd
+}
+
+// passphrase asks for the passphrase of an encrypted SSH key on the terminal.
+func passphrase(name string) ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("%s is encrypted and stdin is not a terminal", name) //nolint
+	}
+
+	fmt.Fprintf(os.Stderr, "Enter passphrase for %s: ", name)
+
+	res, err := term.ReadPassword(fd)
+
+	fmt.Fprintln(os.Stderr)
+
+	//nolint
+	return res, err
+}
+
+func decrypt(name string, identityFiles []string) ([]byte, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	if len(identityFiles) == 0 {
+		identityFiles = encryption.DefaultIdentities()
+	}
+
+	identities, err := encryption.ParseIdentities(identityFiles, passphrase)
+	if err != nil {
+		//nolint
+		return nil, err
+	}
+
+	//nolint
+	return encryption.Decrypt(content, identities)
+}
+
+func run(opt *options.CLI, identities []string, args []string, dash int) error {
+	if len(args) == 0 || dash == 0 {
+		return ErrFilenameRequired
+	}
+
+	plaintext, err := decrypt(args[0], identities)
+	if err != nil {
+		return runError(err)
+	}
+
+	if dash == -1 {
+		if _, err := opt.Writer.Write(plaintext); err != nil {
+			return runError(err)
+		}
+
+		return nil
+	}
+
+	file, err := dotenv.Read(bytes.NewReader(plaintext))
+	if err != nil {
+		return runError(err)
+	}
+
+	code, err := process.Run(args[dash:], process.Options{
+		Env:    process.Environ(os.Environ(), file.Environment()),
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	if err != nil {
+		return runError(err)
+	}
+
+	if code != 0 {
+		return &process.ExitError{Code: code}
+	}
+
+	return nil
+}
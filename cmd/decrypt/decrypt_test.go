@@ -0,0 +1,175 @@
+package decrypt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/eiladin/k8s-dotenv/pkg/encryption"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+)
+
+// encryptedFile writes content encrypted to a new identity and returns the file and identity paths.
+func encryptedFile(t *testing.T, content string) (string, string) {
+	t.Helper()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := encryption.Encrypt([]byte(content), []age.Recipient{identity.Recipient()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, ".env")
+	identityFile := filepath.Join(dir, "keys.txt")
+
+	if err := os.WriteFile(name, encrypted, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return name, identityFile
+}
+
+func TestNewCmd(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		got := NewCmd(&options.CLI{})
+		if got == nil {
+			t.Errorf("NewCmd() is nil want not nil")
+		}
+	})
+
+	t.Run("runE", func(t *testing.T) {
+		got := NewCmd(&options.CLI{})
+		err := got.RunE(got, []string{})
+		if !errors.Is(err, ErrFilenameRequired) {
+			t.Errorf("NewCmd().RunE = %v, want %v", err, ErrFilenameRequired)
+		}
+	})
+
+	t.Run("silence exit errors", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("requires sh")
+		}
+
+		name, identity := encryptedFile(t, "K=v\n")
+		got := NewCmd(&options.CLI{})
+		_ = got.Flags().Set("identity", identity)
+		got.SetArgs([]string{name, "--", "sh", "-c", "exit 2"})
+
+		var exitErr *process.ExitError
+		if err := got.Execute(); !errors.As(err, &exitErr) || exitErr.Code != 2 {
+			t.Errorf("NewCmd().Execute = %v, want exit status 2", err)
+		}
+
+		if !got.SilenceErrors {
+			t.Errorf("NewCmd().SilenceErrors = false, want true")
+		}
+	})
+}
+
+func Test_runError(t *testing.T) {
+	type args struct {
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{name: "wraps error", args: args{err: mock.AnError}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runError(tt.args.err); (err != nil) != tt.wantErr {
+				t.Errorf("runError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	name, identity := encryptedFile(t, "K=v\n")
+	invalid, invalidIdentity := encryptedFile(t, "not a dotenv line\n")
+
+	type args struct {
+		opt        *options.CLI
+		identities []string
+		args       []string
+		dash       int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{name: "error with no args", args: args{dash: -1}, wantErr: true},
+		{name: "error with command but no file", args: args{args: []string{"env"}, dash: 0}, wantErr: true},
+		{
+			name:    "error with missing file",
+			args:    args{identities: []string{identity}, args: []string{filepath.Join(t.TempDir(), "missing")}, dash: -1},
+			wantErr: true,
+		},
+		{
+			name:    "error with wrong identity",
+			args:    args{identities: []string{invalidIdentity}, args: []string{name}, dash: -1},
+			wantErr: true,
+		},
+		{
+			name: "write plaintext",
+			args: args{opt: &options.CLI{Writer: mock.NewWriter()}, identities: []string{identity}, args: []string{name}, dash: -1},
+			want: "K=v\n",
+		},
+		{
+			name: "return writer errors",
+			args: args{
+				opt:        &options.CLI{Writer: mock.NewErrorWriter().ErrorAfter(1)},
+				identities: []string{identity},
+				args:       []string{name},
+				dash:       -1,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error with invalid file for command",
+			args:    args{identities: []string{invalidIdentity}, args: []string{invalid, "env"}, dash: 1},
+			wantErr: true,
+		},
+		{
+			name:    "error with unknown command",
+			args:    args{identities: []string{identity}, args: []string{name, "k8s-dotenv-missing-command"}, dash: 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(tt.args.opt, tt.args.identities, tt.args.args, tt.args.dash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.want != "" {
+				if got := tt.args.opt.Writer.(*mock.Writer).String(); got != tt.want {
+					t.Errorf("run() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
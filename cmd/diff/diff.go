@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/dotenv"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/spf13/cobra"
+)
+
+// ErrResourceNameRequired is returned when no resource name is provided.
+var ErrResourceNameRequired = errors.New("resource name required")
+
+// driftExitCode is the exit code when the local file and the cluster differ, like diff(1).
+const driftExitCode = 1
+
+func runError(err error) error {
+	return fmt.Errorf("diff error: %w", err)
+}
+
+// NewCmd creates the `diff` command.
+func NewCmd(opt *options.CLI) *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "diff RESOURCE_TYPE/RESOURCE_NAME",
+		Short: "compare a local .env file with the environment of a workload",
+		Long: `Compare a local .env file with the environment of a workload and print the keys that were added (+),
+removed (-) or modified (~) in the cluster. When the file has managed blocks only the block of the workload is
+compared. Secret values are redacted unless --redact=false is given. The exit code is 1 when they differ.`,
+		Example: `  k8s-dotenv diff deploy/api
+  k8s-dotenv diff deploy/api --file .env.local --redact-style hash`,
+		Annotations: map[string]string{options.NoOutput: "true"},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return client.WorkloadTypes(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if !c.Flags().Changed("redact") {
+				opt.Output.Redact = true
+			}
+
+			err := run(opt, file, args)
+
+			var exitErr *process.ExitError
+			if errors.As(err, &exitErr) {
+				c.SilenceErrors = true
+				c.SilenceUsage = true
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", ".env", "Local file to compare")
+
+	return cmd
+}
+
+// readFile reads the managed block written for resource in the file, or the whole file when it has no blocks.
+func readFile(name, resource string) (*result.Result, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	block, err := result.BlockContent(string(content), resource)
+	if err != nil {
+		//nolint
+		return nil, err
+	}
+
+	file, err := dotenv.Read(strings.NewReader(block))
+	if err != nil {
+		//nolint
+		return nil, err
+	}
+
+	return file.Result(), nil
+}
+
+func run(opt *options.CLI, file string, args []string) error {
+	if len(args) == 0 {
+		return ErrResourceNameRequired
+	}
+
+	resourceType, name, err := client.ParseResource(args[0])
+	if err != nil {
+		return runError(err)
+	}
+
+	local, err := readFile(file, client.ResourceName(resourceType, name))
+	if err != nil {
+		return runError(err)
+	}
+
+	cluster := client.NewClient(
+		client.WithKubeClient(opt.KubeClient),
+		client.WithNamespace(opt.Namespace),
+		client.WithContainer(opt.Container),
+		client.WithOutput(opt.Output),
+	).Workload(resourceType, name)
+	if cluster.Error != nil {
+		return runError(cluster.Error)
+	}
+
+	changes := result.Diff(local, cluster.DotenvKeys())
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(opt.Writer, "--- %s\n+++ %s\n", file, args[0]); err != nil {
+		return runError(err)
+	}
+
+	if err := result.WriteChanges(opt.Writer, changes, opt.Output); err != nil {
+		return runError(err)
+	}
+
+	return &process.ExitError{Code: driftExitCode}
+}
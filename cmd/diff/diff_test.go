@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestNewCmd(t *testing.T) {
+	kubeClient := mock.NewFakeClient(mock.Pod("test", "test", nil, nil, nil))
+
+	t.Run("create", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		if got == nil {
+			t.Errorf("NewCmd() is nil want not nil")
+		}
+	})
+
+	t.Run("valid args", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		resources, _ := got.ValidArgsFunction(got, []string{}, "")
+		if len(resources) == 0 {
+			t.Errorf("NewCmd().ValidArgs = %v, want resource types", resources)
+		}
+	})
+
+	t.Run("runE", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		err := got.RunE(got, []string{})
+		if !errors.Is(err, ErrResourceNameRequired) {
+			t.Errorf("NewCmd().RunE = %v, want %v", err, ErrResourceNameRequired)
+		}
+	})
+
+	t.Run("redact by default", func(t *testing.T) {
+		opt := &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: mock.NewWriter()}
+		got := NewCmd(opt)
+		_ = got.Flags().Set("file", writeFile(t, "k=v\n"))
+
+		var exitErr *process.ExitError
+		if err := got.RunE(got, []string{"pod/test"}); !errors.As(err, &exitErr) || !got.SilenceErrors {
+			t.Errorf("NewCmd().RunE = %v, want silenced exit error", err)
+		}
+
+		if !opt.Output.Redact {
+			t.Errorf("NewCmd().RunE redact = false, want true")
+		}
+	})
+}
+
+func Test_runError(t *testing.T) {
+	type args struct {
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{name: "wraps error", args: args{err: mock.AnError}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runError(tt.args.err); (err != nil) != tt.wantErr {
+				t.Errorf("runError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	kubeClient := mock.NewFakeClient(
+		mock.Pod("test", "test", map[string]string{"same": "v", "modified": "new"}, nil, []string{"sec"}),
+		mock.Secret("sec", "test", map[string][]byte{"token": []byte("cluster")}),
+		mock.Pod("dotted", "test", map[string]string{"app.name": "api", "pattern": `^\d+\\$`, "quote": `say "hi" # not`}, nil, nil),
+	)
+
+	same := writeFile(t, "same=v\nmodified=new\n##### SECRET - sec #####\ntoken=cluster\n")
+	drift := writeFile(t, "same=v\nmodified=old\nremoved=v\n##### SECRET - sec #####\ntoken=local\n")
+	invalid := writeFile(t, "not a dotenv line\n")
+	blocks := writeFile(t, "# BEGIN k8s-dotenv pod/other\nother=v\n# END k8s-dotenv pod/other\n"+
+		"# BEGIN k8s-dotenv pod/test\nsame=v\nmodified=new\n##### SECRET - sec #####\ntoken=cluster\n# END k8s-dotenv pod/test\n")
+	dotted := writeFile(t, `appname="api"`+"\n"+`pattern="^\\d+\\\\$"`+"\n"+`quote="say \"hi\" # not"`+"\n")
+
+	type args struct {
+		redact bool
+		file   string
+		args   []string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		want     string
+		wantErr  bool
+		wantCode int
+	}{
+		{name: "error with no args", wantErr: true},
+		{name: "error with invalid resource", args: args{file: same, args: []string{"test"}}, wantErr: true},
+		{name: "error with missing file", args: args{file: filepath.Join(t.TempDir(), "missing"), args: []string{"pod/test"}}, wantErr: true},
+		{name: "error with invalid file", args: args{file: invalid, args: []string{"pod/test"}}, wantErr: true},
+		{name: "error with missing pod", args: args{file: same, args: []string{"pod/missing"}}, wantErr: true},
+		{name: "no drift", args: args{file: same, args: []string{"pod/test"}}},
+		{name: "no drift in the block of the pod", args: args{file: blocks, args: []string{"po/test"}}},
+		{name: "error without a block for the pod", args: args{file: blocks, args: []string{"pod/dotted"}}, wantErr: true},
+		{name: "no drift with dotted keys, backslashes and quotes", args: args{file: dotted, args: []string{"pod/dotted"}}},
+		{
+			name:     "drift",
+			args:     args{file: drift, args: []string{"pod/test"}},
+			want:     "--- " + drift + "\n+++ pod/test\n~ modified=old -> new\n- removed=v\n~ token=local -> cluster\n",
+			wantCode: driftExitCode,
+		},
+		{
+			name:     "drift redacted",
+			args:     args{redact: true, file: drift, args: []string{"pod/test"}},
+			want:     "--- " + drift + "\n+++ pod/test\n~ modified=old -> new\n- removed=v\n~ token=******** -> ********\n",
+			wantCode: driftExitCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := mock.NewWriter()
+			opt := &options.CLI{
+				KubeClient: kubeClient,
+				Namespace:  "test",
+				Writer:     writer,
+				Output:     options.Output{Redact: tt.args.redact},
+			}
+
+			err := run(opt, tt.args.file, tt.args.args)
+
+			code := 0
+
+			var exitErr *process.ExitError
+			if errors.As(err, &exitErr) {
+				code, err = exitErr.Code, nil
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if code != tt.wantCode {
+				t.Errorf("run() exit code = %d, want %d", code, tt.wantCode)
+			}
+
+			if got := writer.String(); got != tt.want {
+				t.Errorf("run() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/spf13/cobra"
+)
+
+// ErrResourceNameRequired is returned when no resource name is provided.
+var ErrResourceNameRequired = errors.New("resource name required")
+
+// ErrCommandRequired is returned when no command is provided after `--`.
+var ErrCommandRequired = errors.New("command required after --")
+
+func runError(err error) error {
+	return fmt.Errorf("exec error: %w", err)
+}
+
+// NewCmd creates the `exec` command.
+func NewCmd(opt *options.CLI) *cobra.Command {
+	var clean bool
+
+	cmd := &cobra.Command{
+		Use:   "exec RESOURCE_TYPE/RESOURCE_NAME -- COMMAND [ARGS...]",
+		Short: "run a local command with the environment of a workload",
+		Long: `Run a local command with the environment of a workload merged over the current environment.
+Nothing is written to disk, signals are forwarded to the command and its exit code is returned.
+Use --clean to start the command with only the environment of the workload.`,
+		Example: `  k8s-dotenv exec deploy/api -- go run ./cmd/api
+  k8s-dotenv exec deploy/api --container api --clean -- env`,
+		Annotations: map[string]string{options.NoOutput: "true"},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return client.WorkloadTypes(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			err := run(opt, clean, args, c.ArgsLenAtDash())
+
+			var exitErr *process.ExitError
+			if errors.As(err, &exitErr) {
+				c.SilenceErrors = true
+				c.SilenceUsage = true
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&clean, "clean", false, "Do not pass the current environment to the command")
+
+	return cmd
+}
+
+func run(opt *options.CLI, clean bool, args []string, dash int) error {
+	if len(args) == 0 || dash == 0 {
+		return ErrResourceNameRequired
+	}
+
+	if dash == -1 || dash == len(args) {
+		return ErrCommandRequired
+	}
+
+	resourceType, name, err := client.ParseResource(args[0])
+	if err != nil {
+		return runError(err)
+	}
+
+	env, err := client.NewClient(
+		client.WithKubeClient(opt.KubeClient),
+		client.WithNamespace(opt.Namespace),
+		client.WithContainer(opt.Container),
+		client.WithOutput(opt.Output),
+	).Workload(resourceType, name).Values()
+	if err != nil {
+		return runError(err)
+	}
+
+	base := os.Environ()
+	if clean {
+		base = nil
+	}
+
+	code, err := process.Run(args[dash:], process.Options{
+		Env:    process.Environ(base, env),
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	if err != nil {
+		return runError(err)
+	}
+
+	if code != 0 {
+		return &process.ExitError{Code: code}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package exec
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+)
+
+func TestNewCmd(t *testing.T) {
+	kubeClient := mock.NewFakeClient(mock.Pod("test", "test", nil, nil, nil))
+
+	t.Run("create", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		if got == nil {
+			t.Errorf("NewCmd() is nil want not nil")
+		}
+	})
+
+	t.Run("valid args", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		resources, _ := got.ValidArgsFunction(got, []string{}, "")
+		if len(resources) == 0 {
+			t.Errorf("NewCmd().ValidArgs = %v, want resource types", resources)
+		}
+	})
+
+	t.Run("runE", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		err := got.RunE(got, []string{})
+		if !errors.Is(err, ErrResourceNameRequired) {
+			t.Errorf("NewCmd().RunE = %v, want %v", err, ErrResourceNameRequired)
+		}
+	})
+
+	t.Run("silence exit errors", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("requires sh")
+		}
+
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		got.SetArgs([]string{"pod/test", "--", "sh", "-c", "exit 2"})
+
+		var exitErr *process.ExitError
+		if err := got.Execute(); !errors.As(err, &exitErr) || exitErr.Code != 2 {
+			t.Errorf("NewCmd().Execute = %v, want exit status 2", err)
+		}
+
+		if !got.SilenceErrors {
+			t.Errorf("NewCmd().SilenceErrors = false, want true")
+		}
+	})
+}
+
+func Test_runError(t *testing.T) {
+	type args struct {
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{name: "wraps error", args: args{err: mock.AnError}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runError(tt.args.err); (err != nil) != tt.wantErr {
+				t.Errorf("runError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	t.Setenv("K8S_DOTENV_TEST", "local")
+
+	kubeClient := mock.NewFakeClient(mock.Pod("test", "test", map[string]string{"k": "v"}, nil, nil))
+	opt := &options.CLI{KubeClient: kubeClient, Namespace: "test"}
+
+	type args struct {
+		clean bool
+		args  []string
+		dash  int
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		wantErr  bool
+		wantCode int
+	}{
+		{name: "error with no args", args: args{dash: -1}, wantErr: true},
+		{name: "error with no resource", args: args{args: []string{"sh"}, dash: 0}, wantErr: true},
+		{name: "error with no command", args: args{args: []string{"pod/test"}, dash: -1}, wantErr: true},
+		{name: "error with empty command", args: args{args: []string{"pod/test"}, dash: 1}, wantErr: true},
+		{name: "error with invalid resource", args: args{args: []string{"test", "true"}, dash: 1}, wantErr: true},
+		{name: "error with missing pod", args: args{args: []string{"pod/missing", "true"}, dash: 1}, wantErr: true},
+		{name: "error with unknown command", args: args{args: []string{"pod/test", "k8s-dotenv-missing"}, dash: 1}, wantErr: true},
+		{
+			name: "merge environment",
+			args: args{args: []string{"pod/test", "sh", "-c", `test "$k" = v && test "$K8S_DOTENV_TEST" = local`}, dash: 1},
+		},
+		{
+			name: "clean environment",
+			args: args{clean: true, args: []string{"pod/test", "sh", "-c", `test "$k" = v && test -z "$K8S_DOTENV_TEST"`}, dash: 1},
+		},
+		{name: "return exit code", args: args{args: []string{"pod/test", "sh", "-c", "exit 3"}, dash: 1}, wantCode: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(opt, tt.args.clean, tt.args.args, tt.args.dash)
+
+			code := 0
+
+			var exitErr *process.ExitError
+			if errors.As(err, &exitErr) {
+				code, err = exitErr.Code, nil
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if code != tt.wantCode {
+				t.Errorf("run() exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
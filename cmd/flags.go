@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/spf13/cobra"
+)
+
+// flagSet is the part of a command's flags or persistent flags the flag groups below are added to.
+type flagSet interface {
+	BoolVar(p *bool, name string, value bool, usage string)
+	IntVar(p *int, name string, value int, usage string)
+	StringVar(p *string, name, value, usage string)
+	StringArrayVar(p *[]string, name string, value []string, usage string)
+	StringSliceVar(p *[]string, name string, value []string, usage string)
+}
+
+// addContainerFlag adds the flag choosing the container a workload's environment is read from.
+func addContainerFlag(flags flagSet) {
+	flags.StringVar(&opt.Container, "container", "", "Only use the container with the given name (default all containers)")
+}
+
+// addKeyFlags adds the flags changing which keys are read from a workload and how.
+func addKeyFlags(flags flagSet) {
+	flags.StringArrayVar(&opt.Output.Expand, "expand", nil,
+		"Expand JSON or YAML values of keys matching a glob or a /regex/ into a key per nested value")
+	flags.StringVar(&opt.Output.ExpandSeparator, "expand-separator", "_", "Separator joining the parts of expanded keys")
+	flags.IntVar(&opt.Output.ExpandDepth, "expand-depth", 0,
+		"Number of levels expanded by --expand, deeper values are kept as JSON (default every level)")
+	flags.StringArrayVar(&opt.Output.Include, "include", nil,
+		"Only keep keys matching a glob or a /regex/, prefix with env:, configmap: or secret: to filter one kind of source")
+	flags.StringArrayVar(&opt.Output.Exclude, "exclude", nil,
+		"Drop keys matching a glob or a /regex/, prefix with env:, configmap: or secret: to filter one kind of source")
+	flags.StringArrayVar(&opt.Output.Rename, "rename", nil, "Rename a key, as OLD=NEW")
+	flags.StringArrayVar(&opt.Output.Set, "set", nil, "Set a key to a literal value, as KEY=VALUE")
+	flags.StringArrayVar(&opt.Output.Unset, "unset", nil, "Remove a key")
+	flags.BoolVar(&opt.Output.SecretTypes, "secret-types", false,
+		"Convert basic-auth, ssh-auth, tls and dockerconfigjson Secrets into conventional variables and files")
+	flags.BoolVar(&opt.Output.DockerConfig, "docker-config", false,
+		"Write dockerconfigjson Secrets as a docker config file and set DOCKER_CONFIG (with --secret-types)")
+}
+
+// addRedactFlags adds the flags hiding secret values.
+func addRedactFlags(flags flagSet) {
+	flags.BoolVar(&opt.Output.Redact, "redact", false, "Hide secret values (default true for console output to a terminal)")
+	flags.StringVar(&opt.Output.RedactStyle, "redact-style", result.RedactMask,
+		fmt.Sprintf("How secret values are hidden (%s)", strings.Join(result.RedactStyles(), ", ")))
+	flags.IntVar(&opt.Output.RedactChars, "redact-chars", 4, //nolint
+		"Number of characters shown with the prefix redaction style")
+}
+
+// addFileFlags adds the flags changing how the output file is written.
+func addFileFlags(flags flagSet) {
+	flags.StringVar(&opt.Mode, "mode", "",
+		fmt.Sprintf("How to write to an existing output file (%s), "+
+			"default managed when the format supports comments", strings.Join(result.Modes(), ", ")))
+	flags.BoolVar(&opt.Backup, "backup", false, "Keep a timestamped copy of the output file before replacing it")
+	flags.BoolVar(&opt.AllowUnignored, "allow-unignored", false,
+		"Write secret values to files that are tracked or not ignored by git")
+	flags.StringSliceVar(&opt.EncryptTo, "encrypt-to", nil,
+		"Encrypt the output with age to an age public key, an SSH public key or a file of those, can be repeated")
+}
+
+// addFormatFlags adds the flags choosing the output format and its options.
+func addFormatFlags(flags flagSet) {
+	flags.StringVar(&opt.Output.Format, "format", "dotenv",
+		fmt.Sprintf("Output format (%s)", strings.Join(result.Formats(), ", ")))
+	flags.StringVar(&opt.Output.SystemdUnit, "systemd-unit", "",
+		"Render a systemd drop-in for the given unit (systemd format only)")
+	flags.BoolVar(&opt.Output.SpringKeys, "spring-keys", false,
+		"Convert keys such as SPRING_DATASOURCE_URL to spring.datasource.url (properties, toml and ini formats only)")
+	flags.StringSliceVar(&opt.Output.KeySeparators, "key-separator", []string{"__"},
+		"Separators used to split keys into nested objects (appsettings and nested-json formats only)")
+	flags.StringVar(&opt.Output.TFVarsVariable, "tfvars-variable", "",
+		"Render a single map variable with the given name instead of a variable per key (tfvars format only)")
+	flags.StringVar(&templateFile, "template", "", "Render the output with a Go text/template file")
+	flags.StringVar(&opt.Output.Template, "template-string", "", "Render the output with a Go text/template")
+	flags.StringVar(&opt.Output.RunConfiguration, "run-configuration", "",
+		"Name of the IDE run configuration to create or update (vscode and jetbrains formats only, default resource name)")
+	flags.StringVar(&opt.Output.ConfigMapName, "configmap-name", "",
+		"Name of the generated ConfigMap (k8s format only, default workload name)")
+	flags.StringVar(&opt.Output.SecretName, "secret-name", "",
+		"Name of the generated Secret (k8s format only, default workload name)")
+	flags.StringVar(&opt.Output.ManifestNamespace, "manifest-namespace", "",
+		"Namespace of the generated manifests (k8s format only)")
+	flags.BoolVar(&opt.Output.PatchWorkload, "patch-workload", false,
+		"Include the workload patched to use the generated ConfigMap and Secret (k8s format only)")
+}
+
+// registerCompletions completes the flags of cmd that take one of a fixed list of values.
+func registerCompletions(cmd *cobra.Command) {
+	completions := map[string][]string{
+		"mode":         result.Modes(),
+		"redact-style": result.RedactStyles(),
+		"format":       result.Formats(),
+	}
+
+	for name, values := range completions {
+		if cmd.Flag(name) == nil {
+			continue
+		}
+
+		values := values
+
+		_ = cmd.RegisterFlagCompletionFunc(name,
+			func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				return values, cobra.ShellCompDirectiveNoFileComp
+			})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/eiladin/k8s-dotenv/cmd/get/watch"
 	"github.com/eiladin/k8s-dotenv/pkg/client"
 	"github.com/eiladin/k8s-dotenv/pkg/options"
 	"github.com/spf13/cobra"
@@ -25,6 +26,8 @@ func runError(err error) error {
 
 // NewCmd creates the `cronjob` command.
 func NewCmd(opt *options.CLI) *cobra.Command {
+	var watching bool
+
 	cmd := &cobra.Command{
 		Use:     "cronjob RESOURCE_NAME",
 		Aliases: []string{"cronjobs", "cj"},
@@ -33,10 +36,15 @@ func NewCmd(opt *options.CLI) *cobra.Command {
 			return validArgs(opt), cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(c *cobra.Command, args []string) error {
+			opt.Watch = watching
+
 			return run(opt, args)
 		},
 	}
 
+	cmd.Flags().BoolVarP(&watching, "watch", "w", false,
+		"Rewrite the output whenever the workload or its ConfigMaps and Secrets change, until interrupted")
+
 	return cmd
 }
 
@@ -64,10 +72,20 @@ func run(opt *options.CLI, args []string) error {
 		return ErrResourceNameRequired
 	}
 
+	if opt.Watch {
+		if err := watch.Run(opt, "cronjob", args[0]); err != nil {
+			return runError(err)
+		}
+
+		return nil
+	}
+
 	client := client.NewClient(
 		client.WithKubeClient(opt.KubeClient),
 		client.WithNamespace(opt.Namespace),
 		client.WithExport(!opt.NoExport),
+		client.WithContainer(opt.Container),
+		client.WithOutput(opt.Output),
 	)
 
 	group, err := client.GetAPIGroup("CronJob")
@@ -184,6 +184,14 @@ func Test_run(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "error watching a missing cronjob",
+			args: args{
+				opt:  &options.CLI{KubeClient: v1Client, Namespace: "test", Watch: true, Writer: writer},
+				args: []string{"missing"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "return writer errors",
 			args: args{
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/eiladin/k8s-dotenv/cmd/get/watch"
 	"github.com/eiladin/k8s-dotenv/pkg/client"
 	"github.com/eiladin/k8s-dotenv/pkg/options"
 	"github.com/spf13/cobra"
@@ -18,6 +19,11 @@ func runError(err error) error {
 
 // NewCmd creates the `deployment` command.
 func NewCmd(opt *options.CLI) *cobra.Command {
+	var (
+		revision int64
+		watching bool
+	)
+
 	cmd := &cobra.Command{
 		Use:     "deployment RESOURCE_NAME",
 		Aliases: []string{"deployments", "deploy"},
@@ -26,10 +32,17 @@ func NewCmd(opt *options.CLI) *cobra.Command {
 			return validArgs(opt), cobra.ShellCompDirectiveDefault
 		},
 		RunE: func(c *cobra.Command, args []string) error {
+			opt.Revision = revision
+			opt.Watch = watching
+
 			return run(opt, args)
 		},
 	}
 
+	cmd.Flags().Int64Var(&revision, "revision", 0, "Use the pod template of a past revision (default current)")
+	cmd.Flags().BoolVarP(&watching, "watch", "w", false,
+		"Rewrite the output whenever the workload or its ConfigMaps and Secrets change, until interrupted")
+
 	return cmd
 }
 
@@ -47,10 +60,21 @@ func run(opt *options.CLI, args []string) error {
 		return ErrResourceNameRequired
 	}
 
+	if opt.Watch {
+		if err := watch.Run(opt, "deployment", args[0]); err != nil {
+			return runError(err)
+		}
+
+		return nil
+	}
+
 	err := client.NewClient(
 		client.WithKubeClient(opt.KubeClient),
 		client.WithNamespace(opt.Namespace),
 		client.WithExport(!opt.NoExport),
+		client.WithContainer(opt.Container),
+		client.WithRevision(opt.Revision),
+		client.WithOutput(opt.Output),
 	).AppsV1().Deployment(args[0]).Write(opt.Writer)
 
 	if err != nil {
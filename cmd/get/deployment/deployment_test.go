@@ -90,7 +90,8 @@ func Test_validArgs(t *testing.T) {
 }
 
 func Test_run(t *testing.T) {
-	kubeClient := mock.NewFakeClient(mock.Deployment("test", "test", map[string]string{"k": "v", "k2": "v2"}, nil, nil))
+	deployment := mock.Deployment("test", "test", map[string]string{"k": "v", "k2": "v2"}, nil, nil)
+	kubeClient := mock.NewFakeClient(deployment, mock.DeploymentRevision(deployment, 1, map[string]string{"k": "v1"}))
 	writer := mock.NewWriter()
 
 	type args struct {
@@ -115,6 +116,30 @@ func Test_run(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "find deployment revision",
+			args: args{
+				opt:  &options.CLI{KubeClient: kubeClient, Namespace: "test", Revision: 1, Writer: writer},
+				args: []string{"test"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error with missing revision",
+			args: args{
+				opt:  &options.CLI{KubeClient: kubeClient, Namespace: "test", Revision: 2, Writer: writer},
+				args: []string{"test"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error watching a missing deployment",
+			args: args{
+				opt:  &options.CLI{KubeClient: kubeClient, Namespace: "test", Watch: true, Writer: writer},
+				args: []string{"missing"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "return writer errors",
 			args: args{
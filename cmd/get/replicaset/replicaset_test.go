@@ -115,6 +115,14 @@ func Test_run(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "error watching a missing replicaset",
+			args: args{
+				opt:  &options.CLI{KubeClient: kubeClient, Namespace: "test", Watch: true, Writer: writer},
+				args: []string{"missing"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "return writer errors",
 			args: args{
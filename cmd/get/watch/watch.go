@@ -0,0 +1,71 @@
+// Package watch implements the `--watch` flag of the get commands.
+package watch
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+)
+
+// Run writes the result of a workload, then rewrites it whenever the workload or one of its ConfigMaps or Secrets
+// changes, until SIGINT or SIGTERM is received.
+func Run(opt *options.CLI, resourceType, name string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	//nolint
+	return client.NewClient(
+		client.WithKubeClient(opt.KubeClient),
+		client.WithNamespace(opt.Namespace),
+		client.WithExport(!opt.NoExport),
+		client.WithContainer(opt.Container),
+		client.WithRevision(opt.Revision),
+		client.WithOutput(opt.Output),
+	).Watch(ctx, resourceType, name, updater(opt, resourceType+"/"+name))
+}
+
+// updater returns the function called with each result of the workload.
+//
+// The first result is written, or returned when it has an error. Later results are only written when their values
+// changed, with the changed keys logged. An error in a later result is logged and the output is kept.
+func updater(opt *options.CLI, resource string) func(res *result.Result) error {
+	var previous *result.Result
+
+	return func(res *result.Result) error {
+		if res.Error != nil {
+			if previous == nil {
+				return res.Error
+			}
+
+			log.Printf("%s: %v, keeping the last output", resource, res.Error)
+
+			return nil
+		}
+
+		if previous != nil {
+			changes := result.Diff(previous, res)
+			if len(changes) == 0 {
+				return nil
+			}
+
+			for _, change := range changes {
+				log.Printf("%s: %s %s", resource, change.Type, change.Key)
+			}
+		}
+
+		if err := res.Write(opt.Writer); err != nil {
+			//nolint
+			return err
+		}
+
+		previous = res
+
+		return nil
+	}
+}
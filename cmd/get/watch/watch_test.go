@@ -0,0 +1,88 @@
+package watch
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+)
+
+func TestRun(t *testing.T) {
+	opt := &options.CLI{KubeClient: mock.NewFakeClient(), Namespace: "test", Writer: mock.NewWriter()}
+
+	if err := Run(opt, "svc", "test"); !errors.Is(err, client.ErrUnsupportedType) {
+		t.Errorf("Run() error = %v, want %v", err, client.ErrUnsupportedType)
+	}
+}
+
+func Test_updater(t *testing.T) {
+	var logs bytes.Buffer
+
+	log.SetFlags(0)
+	log.SetOutput(&logs)
+
+	defer log.SetOutput(os.Stderr)
+
+	newResult := func(values map[string]string) *result.Result {
+		return &result.Result{Environment: values}
+	}
+
+	tests := []struct {
+		name      string
+		res       *result.Result
+		wantErr   bool
+		wantWrite bool
+		wantLogs  string
+	}{
+		{name: "error on the first result", res: result.NewFromError(mock.AnError), wantErr: true},
+		{name: "write the first result", res: newResult(map[string]string{"a": "1", "b": "2"}), wantWrite: true},
+		{name: "skip unchanged results", res: newResult(map[string]string{"a": "1", "b": "2"})},
+		{
+			name:      "write changed results",
+			res:       newResult(map[string]string{"a": "2", "c": "3"}),
+			wantWrite: true,
+			wantLogs:  "deploy/test: modified a\ndeploy/test: removed b\ndeploy/test: added c\n",
+		},
+		{
+			name:     "keep the output on later errors",
+			res:      result.NewFromError(mock.AnError),
+			wantLogs: "deploy/test: " + mock.AnError.Error() + ", keeping the last output\n",
+		},
+	}
+
+	writer := mock.NewWriter()
+	update := updater(&options.CLI{Writer: writer}, "deploy/test")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+
+			written := writer.String()
+
+			if err := update(tt.res); (err != nil) != tt.wantErr {
+				t.Errorf("updater() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := writer.String() != written; got != tt.wantWrite {
+				t.Errorf("updater() wrote = %v, want %v", got, tt.wantWrite)
+			}
+
+			if got := logs.String(); got != tt.wantLogs {
+				t.Errorf("updater() logs = %q, want %q", got, tt.wantLogs)
+			}
+		})
+	}
+
+	t.Run("return writer errors", func(t *testing.T) {
+		update := updater(&options.CLI{Writer: mock.NewErrorWriter().ErrorAfter(0)}, "deploy/test")
+		if err := update(newResult(map[string]string{"a": "1"})); err == nil {
+			t.Errorf("updater() error = nil, want error")
+		}
+	})
+}
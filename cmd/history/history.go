@@ -0,0 +1,108 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/spf13/cobra"
+)
+
+// ErrResourceNameRequired is returned when no resource name is provided.
+var ErrResourceNameRequired = errors.New("resource name required")
+
+func runError(err error) error {
+	return fmt.Errorf("history error: %w", err)
+}
+
+// NewCmd creates the `history` command.
+func NewCmd(opt *options.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history RESOURCE_TYPE/RESOURCE_NAME",
+		Short: "list the revisions of a workload with the environment keys changed in each",
+		Long: `List the revisions kept for a deployment (its ReplicaSets) or a daemonset or statefulset (its ControllerRevisions)
+with the keys added (+), removed (-) or modified (~) in each. Values are not shown.
+Use --revision N with get to write the environment of a revision.
+
+Revisions only keep the pod template, ConfigMaps and Secrets are read with their current content.`,
+		Example: `  k8s-dotenv history deploy/api
+  k8s-dotenv get deploy api --revision 3`,
+		Annotations: map[string]string{options.NoOutput: "true"},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{
+				"daemonset", "daemonsets", "ds",
+				"deployment", "deployments", "deploy",
+				"statefulset", "statefulsets", "sts",
+			}, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			return run(opt, args)
+		},
+	}
+
+	return cmd
+}
+
+// changeMark is written before changed keys, like the `diff` command.
+func changeMark(changeType string) string {
+	switch changeType {
+	case result.ChangeAdded:
+		return "+"
+	case result.ChangeRemoved:
+		return "-"
+	}
+
+	return "~"
+}
+
+func run(opt *options.CLI, args []string) error {
+	if len(args) == 0 {
+		return ErrResourceNameRequired
+	}
+
+	resourceType, name, err := client.ParseResource(args[0])
+	if err != nil {
+		return runError(err)
+	}
+
+	clientOptions := &options.Client{Namespace: opt.Namespace, Container: opt.Container}
+
+	revisions, err := client.NewClient(
+		client.WithKubeClient(opt.KubeClient),
+		client.WithNamespace(opt.Namespace),
+	).Revisions(resourceType, name)
+	if err != nil {
+		return runError(err)
+	}
+
+	var res strings.Builder
+
+	previous := &result.Result{}
+
+	for _, revision := range revisions {
+		fmt.Fprintf(&res, "revision %d %s %s\n", revision.Number, revision.Name, revision.Created.UTC().Format(time.RFC3339))
+
+		current := result.NewFromWorkload(opt.KubeClient, clientOptions, revision.Workload)
+		if current.Error != nil {
+			fmt.Fprintf(&res, "  ! %v\n", current.Error)
+
+			continue
+		}
+
+		for _, change := range result.Diff(previous, current) {
+			fmt.Fprintf(&res, "  %s %s\n", changeMark(change.Type), change.Key)
+		}
+
+		previous = current
+	}
+
+	if _, err := opt.Writer.Write([]byte(res.String())); err != nil {
+		return runError(err)
+	}
+
+	return nil
+}
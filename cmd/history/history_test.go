@@ -0,0 +1,127 @@
+package history
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNewCmd(t *testing.T) {
+	kubeClient := mock.NewFakeClient(mock.Deployment("test", "test", nil, nil, nil))
+
+	t.Run("create", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		if got == nil {
+			t.Errorf("NewCmd() is nil want not nil")
+		}
+	})
+
+	t.Run("valid args", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		resources, _ := got.ValidArgsFunction(got, []string{}, "")
+		if len(resources) == 0 {
+			t.Errorf("NewCmd().ValidArgs = %v, want resource types", resources)
+		}
+	})
+
+	t.Run("runE", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		err := got.RunE(got, []string{})
+		if !errors.Is(err, ErrResourceNameRequired) {
+			t.Errorf("NewCmd().RunE = %v, want %v", err, ErrResourceNameRequired)
+		}
+	})
+}
+
+func Test_runError(t *testing.T) {
+	type args struct {
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{name: "wraps error", args: args{err: mock.AnError}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runError(tt.args.err); (err != nil) != tt.wantErr {
+				t.Errorf("runError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	deployment := mock.Deployment("test", "test", nil, nil, nil)
+	missingConfigMap := mock.DeploymentRevision(deployment, 3, nil)
+	missingConfigMap.Spec.Template.Spec.Containers = []corev1.Container{mock.Container(nil, []string{"missing"}, nil)}
+	kubeClient := mock.NewFakeClient(
+		deployment,
+		mock.DeploymentRevision(deployment, 1, map[string]string{"a": "1", "b": "1"}),
+		mock.DeploymentRevision(deployment, 2, map[string]string{"a": "2", "c": "1"}),
+		missingConfigMap,
+		mock.DeploymentRevision(deployment, 4, map[string]string{"a": "2"}),
+	)
+
+	type args struct {
+		opt  *options.CLI
+		args []string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{name: "error with no args", wantErr: true},
+		{
+			name:    "error with invalid resource",
+			args:    args{opt: &options.CLI{KubeClient: kubeClient, Namespace: "test"}, args: []string{"test"}},
+			wantErr: true,
+		},
+		{
+			name:    "error with unsupported type",
+			args:    args{opt: &options.CLI{KubeClient: kubeClient, Namespace: "test"}, args: []string{"pod/test"}},
+			wantErr: true,
+		},
+		{
+			name: "list revisions",
+			args: args{opt: &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: mock.NewWriter()}, args: []string{"deploy/test"}},
+			want: "revision 1 test-1 0001-01-01T00:00:00Z\n  + a\n  + b\n" +
+				"revision 2 test-2 0001-01-01T00:00:00Z\n  ~ a\n  - b\n  + c\n" +
+				"revision 3 test-3 0001-01-01T00:00:00Z\n  ! resource not found\n" +
+				"revision 4 test-4 0001-01-01T00:00:00Z\n  - c\n",
+		},
+		{
+			name: "return writer errors",
+			args: args{
+				opt:  &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: mock.NewErrorWriter().ErrorAfter(1)},
+				args: []string{"deploy/test"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(tt.args.opt, tt.args.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.want != "" {
+				if got := tt.args.opt.Writer.(*mock.Writer).String(); got != tt.want {
+					t.Errorf("run() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/encryption"
+	"github.com/eiladin/k8s-dotenv/pkg/git"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+)
+
+const (
+	dirPerm     fs.FileMode = 0o755
+	executePerm fs.FileMode = 0o111
+)
+
+// ErrUnignoredOutput is returned when secret values would be written to a file git could commit.
+var ErrUnignoredOutput = errors.New("output file is not ignored by git")
+
+// backupTimeFormat is used in the name of backups, `.env` is backed up as `.env.20220131T150405.bak`.
+const backupTimeFormat = "20060102T150405"
+
+// outputFile writes the rendered output to the output file according to the write mode.
+//
+// The mode is resolved when writing since commands such as `script` choose the format when they run.
+// The file is replaced atomically, with 0600 permissions when the output contains secret values.
+type outputFile struct {
+	name      string
+	backup    bool
+	secrets   bool
+	encrypted bool
+}
+
+// SetContainsSecrets is called by `Result.Write` before writing.
+func (f *outputFile) SetContainsSecrets(secrets bool) {
+	f.secrets = secrets
+}
+
+func (f *outputFile) Write(data []byte) (int, error) {
+	mode, err := result.WriteMode(opt.Output.Format, opt.Mode)
+	if err != nil {
+		//nolint
+		return 0, err
+	}
+
+	if f.encrypted {
+		if opt.Mode != "" && opt.Mode != result.ModeOverwrite {
+			return 0, fmt.Errorf("%w: %s cannot be used with --encrypt-to", result.ErrUnsupportedMode, opt.Mode)
+		}
+
+		mode = result.ModeOverwrite
+	}
+
+	if err := checkGit(f.name, f.secrets && !f.encrypted); err != nil {
+		return 0, err
+	}
+
+	perm := result.FilePerm
+	if f.secrets {
+		perm = result.SecretFilePerm
+	}
+
+	err = replaceFile(f.name, perm, f.backup, f.secrets && !f.encrypted, func(original []byte) ([]byte, error) {
+		switch mode {
+		case result.ModeAppend:
+			return append(original, data...), nil
+		case result.ModeManaged:
+			content, err := result.ManagedBlock(string(original), string(data), opt.ResourceName)
+
+			//nolint
+			return []byte(content), err
+		}
+
+		return data, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// encryptWriter encrypts the output to the `--encrypt-to` recipients before writing it to next.
+type encryptWriter struct {
+	next       io.Writer
+	recipients []age.Recipient
+}
+
+// SetContainsSecrets is passed on to next.
+func (w *encryptWriter) SetContainsSecrets(secrets bool) {
+	if secretWriter, ok := w.next.(result.SecretWriter); ok {
+		secretWriter.SetContainsSecrets(secrets)
+	}
+}
+
+func (w *encryptWriter) Write(data []byte) (int, error) {
+	encrypted, err := encryption.Encrypt(data, w.recipients)
+	if err != nil {
+		//nolint
+		return 0, err
+	}
+
+	if _, err := w.next.Write(encrypted); err != nil {
+		//nolint
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// checkGit refuses to write secrets to a file that is tracked or not ignored by git, or when that cannot be checked,
+// and warns for other values, unless `--allow-unignored` is set.
+func checkGit(name string, secrets bool) error {
+	if opt.AllowUnignored {
+		return nil
+	}
+
+	status, err := git.PathStatus(name)
+	if err != nil && secrets {
+		return fmt.Errorf("%w: could not check whether %s is ignored by git and it contains secret values, "+
+			"use --allow-unignored to write anyway: %s", ErrUnignoredOutput, name, err.Error())
+	}
+
+	if err != nil {
+		log.Printf("warning: could not check whether %s is ignored by git: %v", name, err)
+
+		return nil
+	}
+
+	if !status.Exposed() {
+		return nil
+	}
+
+	reason := "not ignored"
+	if status.Tracked {
+		reason = "tracked"
+	}
+
+	if secrets {
+		return fmt.Errorf("%w: %s is %s in %s and contains secret values, add it to .gitignore or use --allow-unignored",
+			ErrUnignoredOutput, name, reason, status.Repository)
+	}
+
+	log.Printf("warning: %s is %s in %s, add it to .gitignore", name, reason, status.Repository)
+
+	return nil
+}
+
+// replaceFile atomically replaces name with the content returned by update, given the previous content.
+//
+// Permissions of an existing file are never loosened, perm only removes access. When backup is set the
+// previous version is kept next to the file with a timestamp in its name, it goes through the same git check as the
+// file since it holds the same kind of values.
+func replaceFile(name string, perm fs.FileMode, backup, secrets bool, update func(original []byte) ([]byte, error)) error {
+	if target, err := filepath.EvalSymlinks(name); err == nil {
+		name = target
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), dirPerm); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	info, err := os.Stat(name)
+	exists := err == nil
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading output file: %w", err)
+	}
+
+	var original []byte
+
+	if exists {
+		perm = info.Mode().Perm() & (perm | executePerm)
+
+		if original, err = os.ReadFile(name); err != nil {
+			return fmt.Errorf("reading output file: %w", err)
+		}
+	}
+
+	content, err := update(original)
+	if err != nil {
+		return err
+	}
+
+	if backup && exists {
+		backupName := fmt.Sprintf("%s.%s.bak", name, time.Now().Format(backupTimeFormat))
+		if err := checkGit(backupName, secrets); err != nil {
+			return err
+		}
+
+		if err := writeAtomic(backupName, original, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	return writeAtomic(name, content, perm)
+}
+
+// writeAtomic writes data to a temporary file in the same directory and renames it to name,
+// so readers never see a partially written file.
+func writeAtomic(name string, data []byte, perm fs.FileMode) error {
+	temp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+
+	defer os.Remove(temp.Name()) //nolint
+
+	if _, err := temp.Write(data); err != nil {
+		_ = temp.Close()
+
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+
+	if err := temp.Chmod(perm); err != nil {
+		_ = temp.Close()
+
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+
+	if err := temp.Sync(); err != nil {
+		_ = temp.Close()
+
+		return fmt.Errorf("syncing temporary file: %w", err)
+	}
+
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+
+	if err := os.Rename(temp.Name(), name); err != nil {
+		return fmt.Errorf("replacing output file: %w", err)
+	}
+
+	return nil
+}
+
+// resourceName identifies the resource in managed block markers, such as `deployment/api` for `deploy/api`.
+func resourceName(name string, args []string) string {
+	if len(args) == 0 {
+		return name
+	}
+
+	if resourceType, resource, found := strings.Cut(args[0], "/"); found {
+		return client.ResourceName(resourceType, resource)
+	}
+
+	return client.ResourceName(name, args[0])
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
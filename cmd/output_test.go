@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/eiladin/k8s-dotenv/pkg/encryption"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+)
+
+func Test_outputFile_Write(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		perm     os.FileMode
+		mode     string
+		secrets  bool
+		want     string
+		wantPerm os.FileMode
+	}{
+		{name: "new file", mode: "overwrite", want: "A=1\n", wantPerm: 0o644},
+		{name: "new file with secrets", mode: "overwrite", secrets: true, want: "A=1\n", wantPerm: 0o600},
+		{
+			name:     "tighten permissions for secrets",
+			original: "B=2\n",
+			perm:     0o644,
+			mode:     "overwrite",
+			secrets:  true,
+			want:     "A=1\n",
+			wantPerm: 0o600,
+		},
+		{name: "never loosen permissions", original: "B=2\n", perm: 0o600, mode: "append", want: "B=2\nA=1\n", wantPerm: 0o600},
+		{
+			name:     "managed block",
+			original: "B=2\n",
+			perm:     0o640,
+			want:     "B=2\n# BEGIN k8s-dotenv deployment/api\nA=1\n# END k8s-dotenv deployment/api\n",
+			wantPerm: 0o640,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), ".env")
+			if tt.original != "" {
+				if err := os.WriteFile(name, []byte(tt.original), tt.perm); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.Chmod(name, tt.perm); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			opt = &options.CLI{Mode: tt.mode, ResourceName: "deployment/api"}
+			file := &outputFile{name: name}
+			file.SetContainsSecrets(tt.secrets)
+
+			if _, err := file.Write([]byte("A=1\n")); err != nil {
+				t.Fatalf("outputFile.Write() error = %v", err)
+			}
+
+			got, _ := os.ReadFile(name)
+			if string(got) != tt.want {
+				t.Errorf("outputFile.Write() = %q, want %q", got, tt.want)
+			}
+
+			info, _ := os.Stat(name)
+			if info.Mode().Perm() != tt.wantPerm {
+				t.Errorf("outputFile.Write() perm = %v, want %v", info.Mode().Perm(), tt.wantPerm)
+			}
+		})
+	}
+}
+
+func Test_replaceFile_backup(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, ".env")
+
+	if err := os.WriteFile(name, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opt = &options.CLI{}
+
+	err := replaceFile(name, result.FilePerm, true, false, func(original []byte) ([]byte, error) {
+		return []byte("new"), nil
+	})
+	if err != nil {
+		t.Fatalf("replaceFile() error = %v", err)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, ".env.*.bak"))
+	if len(backups) != 1 {
+		t.Fatalf("replaceFile() backups = %v, want 1", backups)
+	}
+
+	if got, _ := os.ReadFile(backups[0]); string(got) != "old" {
+		t.Errorf("replaceFile() backup = %q, want %q", got, "old")
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("replaceFile() left temporary files: %v", entries)
+	}
+}
+
+func Test_replaceFile_unignoredBackup(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	opt = &options.CLI{}
+	root := t.TempDir()
+	name := filepath.Join(root, ".env")
+
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(".env\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(name, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := replaceFile(name, result.SecretFilePerm, true, true, func(original []byte) ([]byte, error) {
+		return []byte("new"), nil
+	})
+	if !errors.Is(err, ErrUnignoredOutput) {
+		t.Errorf("replaceFile() error = %v, want %v", err, ErrUnignoredOutput)
+	}
+
+	if got, _ := os.ReadFile(name); string(got) != "old" {
+		t.Errorf("replaceFile() replaced the file with an unignored backup")
+	}
+}
+
+func Test_checkGit(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(".env\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	broken := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(broken, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(broken, ".git", "index"), []byte("not an index"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		root           string
+		file           string
+		secrets        bool
+		allowUnignored bool
+		wantErr        error
+	}{
+		{name: "ignored file", file: ".env", secrets: true},
+		{name: "warn without secrets", file: "config.env"},
+		{name: "error with secrets", file: "config.env", secrets: true, wantErr: ErrUnignoredOutput},
+		{name: "allow unignored", file: "config.env", secrets: true, allowUnignored: true},
+		{name: "warn on unreadable index without secrets", root: broken, file: ".env"},
+		{name: "error on unreadable index with secrets", root: broken, file: ".env", secrets: true, wantErr: ErrUnignoredOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt = &options.CLI{AllowUnignored: tt.allowUnignored}
+
+			dir := root
+			if tt.root != "" {
+				dir = tt.root
+			}
+
+			if err := checkGit(filepath.Join(dir, tt.file), tt.secrets); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkGit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_encryptWriter_Write(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr error
+	}{
+		{name: "replace file"},
+		{name: "overwrite mode", mode: "overwrite"},
+		{name: "append mode", mode: "append", wantErr: result.ErrUnsupportedMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(name, []byte("B=2\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			opt = &options.CLI{Mode: tt.mode, ResourceName: "deployment/api"}
+			writer := &encryptWriter{
+				next:       &outputFile{name: name, encrypted: true},
+				recipients: []age.Recipient{identity.Recipient()},
+			}
+			writer.SetContainsSecrets(true)
+
+			_, err := writer.Write([]byte("A=1\n"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("encryptWriter.Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			content, _ := os.ReadFile(name)
+
+			got, err := encryption.Decrypt(content, []age.Identity{identity})
+			if err != nil || string(got) != "A=1\n" {
+				t.Errorf("encryptWriter.Write() = %q, %v, want %q", got, err, "A=1\n")
+			}
+
+			if info, _ := os.Stat(name); info.Mode().Perm() != result.SecretFilePerm {
+				t.Errorf("encryptWriter.Write() perm = %v, want %v", info.Mode().Perm(), result.SecretFilePerm)
+			}
+		})
+	}
+}
+
+func Test_resourceName(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    string
+	}{
+		{name: "get command", command: "deployment", args: []string{"api"}, want: "deployment/api"},
+		{name: "type alias", command: "script", args: []string{"deploy/api"}, want: "deployment/api"},
+		{name: "no args", command: "deployment", want: "deployment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resourceName(tt.command, tt.args); got != tt.want {
+				t.Errorf("resourceName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
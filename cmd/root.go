@@ -3,16 +3,32 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"path"
+	"path/filepath"
 
+	"filippo.io/age"
+	"github.com/eiladin/k8s-dotenv/cmd/apply"
+	"github.com/eiladin/k8s-dotenv/cmd/compare"
 	"github.com/eiladin/k8s-dotenv/cmd/completion"
+	"github.com/eiladin/k8s-dotenv/cmd/decrypt"
+	"github.com/eiladin/k8s-dotenv/cmd/diff"
 	"github.com/eiladin/k8s-dotenv/cmd/doc"
+	"github.com/eiladin/k8s-dotenv/cmd/exec"
 	"github.com/eiladin/k8s-dotenv/cmd/get"
+	"github.com/eiladin/k8s-dotenv/cmd/history"
+	"github.com/eiladin/k8s-dotenv/cmd/script"
 	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/encryption"
 	"github.com/eiladin/k8s-dotenv/pkg/kubeclient"
 	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/process"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"k8s.io/client-go/kubernetes"
 )
 
 // ErrNoFilename is returned when no filename is provided.
@@ -24,11 +40,154 @@ var opt *options.CLI = &options.CLI{}
 //nolint
 var stdOut bool
 
+//nolint
+var templateFile string
+
+// ErrTemplateFormat is returned when a template is combined with a format other than `template`.
+var ErrTemplateFormat = errors.New("--template and --template-string can only be used with the template format")
+
+// ErrTemplateConflict is returned when both a template file and a template string are provided.
+var ErrTemplateConflict = errors.New("--template and --template-string are mutually exclusive")
+
+// ErrEncryptedCompanionFiles is returned when an encrypted output would refer to companion files, which tools
+// cannot read once encrypted.
+var ErrEncryptedCompanionFiles = errors.New("--secret-types and the kustomize format cannot be used with --encrypt-to")
+
 // Execute creates the `k8s-dotenv` command with version and calls execute.
 func Execute(version string, args []string) {
 	newRootCmd(version).execute(args)
 }
 
+// resolveTemplate loads the template file and switches to the template format when a template is provided.
+func resolveTemplate(cmd *cobra.Command) error {
+	if templateFile != "" {
+		if opt.Output.Template != "" {
+			return ErrTemplateConflict
+		}
+
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("reading template: %w", err)
+		}
+
+		opt.Output.Template = string(content)
+	}
+
+	if opt.Output.Template == "" {
+		return nil
+	}
+
+	if cmd.Flags().Changed("format") && opt.Output.Format != "template" {
+		return ErrTemplateFormat
+	}
+
+	opt.Output.Format = "template"
+
+	return nil
+}
+
+// companionFileWriter writes files created alongside the output into dir.
+func companionFileWriter(dir string) func(name string, data []byte, perm os.FileMode) error {
+	return func(name string, data []byte, perm os.FileMode) error {
+		secrets := perm == result.SecretFilePerm
+		if err := checkGit(filepath.Join(dir, name), secrets); err != nil {
+			return err
+		}
+
+		return replaceFile(filepath.Join(dir, name), perm, opt.Backup, secrets, func([]byte) ([]byte, error) {
+			return data, nil
+		})
+	}
+}
+
+// runConfigurationName is the IDE run configuration name, defaulting to the resource name.
+func runConfigurationName(args []string) string {
+	if opt.Output.RunConfiguration != "" {
+		return opt.Output.RunConfiguration
+	}
+
+	if len(args) > 0 {
+		return path.Base(args[0])
+	}
+
+	return "k8s-dotenv"
+}
+
+// newKubeClient returns a client for a kubeconfig context, the current context is used when no context is given.
+func newKubeClient(context string) (kubernetes.Interface, error) {
+	if context == "" {
+		//nolint
+		return kubeclient.GetDefault()
+	}
+
+	//nolint
+	return kubeclient.ForContext(context)
+}
+
+// setupOutput validates the output flags and sets the writer to the console or the output file.
+func setupOutput(cmd *cobra.Command, args []string) error {
+	if err := resolveTemplate(cmd); err != nil {
+		return err
+	}
+
+	var recipients []age.Recipient
+
+	if len(opt.EncryptTo) > 0 {
+		var err error
+		if recipients, err = encryption.ParseRecipients(opt.EncryptTo); err != nil {
+			//nolint
+			return err
+		}
+	}
+
+	if stdOut || (cmd.Annotations[options.DefaultConsole] != "" && !cmd.Flags().Changed("outfile")) {
+		opt.Writer = os.Stdout
+
+		if !cmd.Flags().Changed("redact") {
+			opt.Output.Redact = term.IsTerminal(int(os.Stdout.Fd()))
+		}
+	} else {
+		if !cmd.Flags().Changed("outfile") {
+			if name := result.DefaultFilename(opt.Output.Format, runConfigurationName(args)); name != "" {
+				opt.Filename = name
+			}
+		}
+
+		if opt.Filename == "" {
+			return ErrNoFilename
+		}
+
+		if opt.Mode != "" && !contains(result.Modes(), opt.Mode) {
+			return fmt.Errorf("%w: %s", result.ErrUnsupportedMode, opt.Mode)
+		}
+
+		if len(recipients) > 0 && result.WritesCompanionFiles(opt.Output) {
+			return ErrEncryptedCompanionFiles
+		}
+
+		if result.UpdatesFile(opt.Output.Format) {
+			original, err := os.ReadFile(opt.Filename)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("reading output file: %w", err)
+			}
+
+			opt.Output.Original = original
+		}
+
+		opt.ResourceName = resourceName(cmd.Name(), args)
+		opt.Writer = &outputFile{name: opt.Filename, backup: opt.Backup, encrypted: len(recipients) > 0}
+		opt.Output.CommandWriter = os.Stdout
+		opt.Output.WriteFile = companionFileWriter(filepath.Dir(opt.Filename))
+		opt.Output.FileDir, _ = filepath.Abs(filepath.Dir(opt.Filename))
+	}
+
+	if len(recipients) > 0 {
+		opt.Writer = &encryptWriter{next: opt.Writer, recipients: recipients}
+	}
+
+	return nil
+}
+
 type rootCmd struct {
 	cmd *cobra.Command
 }
@@ -37,6 +196,11 @@ func (cmd *rootCmd) execute(args []string) {
 	cmd.cmd.SetArgs(args)
 
 	if err := cmd.cmd.Execute(); err != nil {
+		var exitErr *process.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+
 		log.Fatal(err)
 	}
 }
@@ -50,7 +214,7 @@ func newRootCmd(version string) *rootCmd {
 		Long:  `k8s-dotenv takes a kubernetes secret or configmap and turns it into a .env file.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			log.SetFlags(0)
-			kubeClient, err := kubeclient.GetDefault()
+			kubeClient, err := newKubeClient(opt.Context)
 			if err != nil {
 				//nolint
 				return err
@@ -58,20 +222,19 @@ func newRootCmd(version string) *rootCmd {
 
 			opt.KubeClient = kubeClient
 
-			if stdOut {
-				opt.Writer = os.Stdout
-			} else {
-				if opt.Filename == "" {
-					return ErrNoFilename
-				}
+			if !contains(result.RedactStyles(), opt.Output.RedactStyle) {
+				return fmt.Errorf("%w: %s", result.ErrUnsupportedRedaction, opt.Output.RedactStyle)
+			}
 
+			if err := result.ValidateFilters(opt.Output); err != nil {
 				//nolint
-				f, err := os.OpenFile(opt.Filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
-				if err != nil {
-					return fmt.Errorf("creating output file: %w", err)
-				}
+				return err
+			}
 
-				opt.Writer = f
+			if cmd.Annotations[options.NoOutput] != "" {
+				opt.Writer = os.Stdout
+			} else if err := setupOutput(cmd, args); err != nil {
+				return err
 			}
 
 			if opt.Namespace == "" {
@@ -86,6 +249,7 @@ func newRootCmd(version string) *rootCmd {
 		Version: version,
 	}
 
+	cmd.PersistentFlags().StringVar(&opt.Context, "context", "", "Kubeconfig context (default current context)")
 	cmd.PersistentFlags().StringVarP(&opt.Namespace, "namespace", "n", "", "Namespace (default current context namespace)")
 	cmd.PersistentFlags().StringVarP(&opt.Filename, "outfile", "o", ".env", "Output file")
 	cmd.PersistentFlags().BoolVarP(&opt.NoExport, "no-export", "e", false, "Do not include `export` statements")
@@ -101,12 +265,56 @@ func newRootCmd(version string) *rootCmd {
 			return list, cobra.ShellCompDirectiveDefault
 		})
 
+	applyCmd := apply.NewCmd(opt)
+	addContainerFlag(applyCmd.Flags())
+	addRedactFlags(applyCmd.Flags())
+
+	compareCmd := compare.NewCmd(opt)
+	addContainerFlag(compareCmd.Flags())
+	addKeyFlags(compareCmd.Flags())
+
+	diffCmd := diff.NewCmd(opt)
+	addContainerFlag(diffCmd.Flags())
+	addKeyFlags(diffCmd.Flags())
+	addRedactFlags(diffCmd.Flags())
+
+	execCmd := exec.NewCmd(opt)
+	addContainerFlag(execCmd.Flags())
+	addKeyFlags(execCmd.Flags())
+
+	getCmd := get.NewCmd(opt)
+	addContainerFlag(getCmd.PersistentFlags())
+	addKeyFlags(getCmd.PersistentFlags())
+	addRedactFlags(getCmd.PersistentFlags())
+	addFileFlags(getCmd.PersistentFlags())
+	addFormatFlags(getCmd.PersistentFlags())
+
+	historyCmd := history.NewCmd(opt)
+	addContainerFlag(historyCmd.Flags())
+
+	scriptCmd := script.NewCmd(opt)
+	addContainerFlag(scriptCmd.Flags())
+	addKeyFlags(scriptCmd.Flags())
+	addRedactFlags(scriptCmd.Flags())
+	addFileFlags(scriptCmd.Flags())
+
 	cmd.AddCommand(
+		applyCmd,
+		compareCmd,
 		completion.NewCmd(opt),
-		get.NewCmd(opt),
+		decrypt.NewCmd(opt),
+		diffCmd,
+		getCmd,
+		historyCmd,
 		doc.NewCmd(opt),
+		execCmd,
+		scriptCmd,
 	)
 
+	for _, child := range []*cobra.Command{applyCmd, diffCmd, getCmd, scriptCmd} {
+		registerCompletions(child)
+	}
+
 	root.cmd = cmd
 
 	return root
@@ -0,0 +1,73 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eiladin/k8s-dotenv/pkg/client"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/spf13/cobra"
+)
+
+// ErrResourceNameRequired is returned when no resource name is provided.
+var ErrResourceNameRequired = errors.New("resource name required")
+
+func runError(err error) error {
+	return fmt.Errorf("script error: %w", err)
+}
+
+// NewCmd creates the `script` command.
+func NewCmd(opt *options.CLI) *cobra.Command {
+	var docker bool
+
+	cmd := &cobra.Command{
+		Use:   "script RESOURCE_TYPE/RESOURCE_NAME",
+		Short: "generate a shell script that runs a container locally with its environment",
+		Long: `Generate a shell script that reproduces the command, args, working directory and environment of a container.
+$(VAR) references in the command and args are expanded the same way the kubelet does.
+Use --docker to generate a docker run command line instead.`,
+		Example: `  k8s-dotenv script deploy/api
+  k8s-dotenv script deploy/api --container api --docker`,
+		Annotations: map[string]string{options.DefaultConsole: "true"},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return client.WorkloadTypes(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			opt.Output.Format = "script"
+			if docker {
+				opt.Output.Format = "docker"
+			}
+
+			return run(opt, args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&docker, "docker", false, "Generate a docker run command line")
+
+	return cmd
+}
+
+func run(opt *options.CLI, args []string) error {
+	if len(args) == 0 {
+		return ErrResourceNameRequired
+	}
+
+	resourceType, name, err := client.ParseResource(args[0])
+	if err != nil {
+		return runError(err)
+	}
+
+	err = client.NewClient(
+		client.WithKubeClient(opt.KubeClient),
+		client.WithNamespace(opt.Namespace),
+		client.WithExport(!opt.NoExport),
+		client.WithContainer(opt.Container),
+		client.WithOutput(opt.Output),
+	).Workload(resourceType, name).Write(opt.Writer)
+
+	if err != nil {
+		return runError(err)
+	}
+
+	return nil
+}
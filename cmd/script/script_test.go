@@ -0,0 +1,131 @@
+package script
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+)
+
+func TestNewCmd(t *testing.T) {
+	kubeClient := mock.NewFakeClient(mock.Pod("test", "test", nil, nil, nil))
+
+	t.Run("create", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		if got == nil {
+			t.Errorf("NewCmd() is nil want not nil")
+		}
+	})
+
+	t.Run("valid args", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		resources, _ := got.ValidArgsFunction(got, []string{}, "")
+		if len(resources) == 0 {
+			t.Errorf("NewCmd().ValidArgs = %v, want resource types", resources)
+		}
+	})
+
+	t.Run("runE", func(t *testing.T) {
+		got := NewCmd(&options.CLI{KubeClient: kubeClient, Namespace: "test"})
+		err := got.RunE(got, []string{})
+		if !errors.Is(err, ErrResourceNameRequired) {
+			t.Errorf("NewCmd().RunE = %v, want %v", err, ErrResourceNameRequired)
+		}
+	})
+
+	t.Run("docker format", func(t *testing.T) {
+		opt := &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: mock.NewWriter()}
+		got := NewCmd(opt)
+		_ = got.Flags().Set("docker", "true")
+		_ = got.RunE(got, []string{"pod/test"})
+		if opt.Output.Format != "docker" {
+			t.Errorf("NewCmd().RunE format = %v, want docker", opt.Output.Format)
+		}
+	})
+}
+
+func Test_runError(t *testing.T) {
+	type args struct {
+		err error
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{name: "wraps error", args: args{err: mock.AnError}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runError(tt.args.err); (err != nil) != tt.wantErr {
+				t.Errorf("runError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	kubeClient := mock.NewFakeClient(mock.Pod("test", "test", map[string]string{"k": "v"}, nil, nil))
+	writer := mock.NewWriter()
+
+	type args struct {
+		opt  *options.CLI
+		args []string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "error with no args",
+			wantErr: true,
+		},
+		{
+			name: "error with invalid resource",
+			args: args{
+				opt:  &options.CLI{KubeClient: kubeClient, Namespace: "test", Writer: writer},
+				args: []string{"test"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "write script",
+			args: args{
+				opt: &options.CLI{
+					KubeClient: kubeClient,
+					Namespace:  "test",
+					Writer:     writer,
+					Output:     options.Output{Format: "script"},
+				},
+				args: []string{"pod/test"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "return writer errors",
+			args: args{
+				opt: &options.CLI{
+					KubeClient: kubeClient,
+					Namespace:  "test",
+					Writer:     mock.NewErrorWriter().ErrorAfter(1),
+					Output:     options.Output{Format: "script"},
+				},
+				args: []string{"pod/test"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := run(tt.args.opt, tt.args.args); (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
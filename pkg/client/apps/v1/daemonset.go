@@ -18,13 +18,35 @@ func (appsv1 *AppsV1) DaemonSet(resource string) *result.Result {
 		return result.NewFromError(NewResourceLoadError("DaemonSet", err))
 	}
 
-	return result.NewFromContainers(
+	if appsv1.options.Revision == 0 {
+		return result.NewFromWorkload(appsv1.kubeClient, appsv1.options, resp)
+	}
+
+	revisions, err := appsv1.daemonSetRevisions(resp)
+	if err != nil {
+		return result.NewFromError(err)
+	}
+
+	workload, err := findRevision(revisions, appsv1.options.Revision)
+	if err != nil {
+		return result.NewFromError(err)
+	}
+
+	return result.NewFromWorkload(appsv1.kubeClient, appsv1.options, workload)
+}
+
+// DaemonSetRevisions returns the revisions of the daemonset with the given name, oldest first.
+func (appsv1 *AppsV1) DaemonSetRevisions(resource string) ([]Revision, error) {
+	resp, err := appsv1.
+		AppsV1Interface.
+		DaemonSets(appsv1.options.Namespace).
+		Get(context.TODO(), resource, metav1.GetOptions{})
+
+	if err != nil {
+		return nil, NewResourceLoadError("DaemonSet", err)
+	}
 
-		appsv1.kubeClient,
-		appsv1.options.Namespace,
-		appsv1.options.ShouldExport,
-		resp.Spec.Template.Spec.Containers,
-	)
+	return appsv1.daemonSetRevisions(resp)
 }
 
 // DaemonSetList returns a list of daemonsets.
@@ -18,12 +18,35 @@ func (appsv1 *AppsV1) Deployment(resource string) *result.Result {
 		return result.NewFromError(NewResourceLoadError("Deployment", err))
 	}
 
-	return result.NewFromContainers(
-		appsv1.kubeClient,
-		appsv1.options.Namespace,
-		appsv1.options.ShouldExport,
-		resp.Spec.Template.Spec.Containers,
-	)
+	if appsv1.options.Revision == 0 {
+		return result.NewFromWorkload(appsv1.kubeClient, appsv1.options, resp)
+	}
+
+	revisions, err := appsv1.deploymentRevisions(resp)
+	if err != nil {
+		return result.NewFromError(err)
+	}
+
+	workload, err := findRevision(revisions, appsv1.options.Revision)
+	if err != nil {
+		return result.NewFromError(err)
+	}
+
+	return result.NewFromWorkload(appsv1.kubeClient, appsv1.options, workload)
+}
+
+// DeploymentRevisions returns the revisions of the deployment with the given name, oldest first.
+func (appsv1 *AppsV1) DeploymentRevisions(resource string) ([]Revision, error) {
+	resp, err := appsv1.
+		AppsV1Interface.
+		Deployments(appsv1.options.Namespace).
+		Get(context.TODO(), resource, metav1.GetOptions{})
+
+	if err != nil {
+		return nil, NewResourceLoadError("Deployment", err)
+	}
+
+	return appsv1.deploymentRevisions(resp)
 }
 
 // DeploymentList returns a list of depployments.
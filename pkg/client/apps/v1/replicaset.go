@@ -18,11 +18,10 @@ func (appsv1 *AppsV1) ReplicaSet(resource string) *result.Result {
 		return result.NewFromError(NewResourceLoadError("ReplicaSet", err))
 	}
 
-	return result.NewFromContainers(
+	return result.NewFromWorkload(
 		appsv1.kubeClient,
-		appsv1.options.Namespace,
-		appsv1.options.ShouldExport,
-		resp.Spec.Template.Spec.Containers,
+		appsv1.options,
+		resp,
 	)
 }
 
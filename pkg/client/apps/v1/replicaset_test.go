@@ -36,6 +36,7 @@ func TestAppsV1_ReplicaSet(t *testing.T) {
 				Environment: result.EnvValues{"k": "v"},
 				Secrets:     map[string]result.EnvValues{"test": {"k": "v"}},
 				ConfigMaps:  map[string]result.EnvValues{"test": {"k": "v"}},
+				Workload:    mockv1,
 			},
 		},
 		{
@@ -50,6 +51,7 @@ func TestAppsV1_ReplicaSet(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			opts := []cmp.Option{
 				cmp.AllowUnexported(result.Result{}),
+				cmpopts.IgnoreFields(result.Result{}, "envFrom"),
 				cmpopts.EquateErrors(),
 			}
 
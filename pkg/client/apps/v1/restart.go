@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RestartedAtAnnotation is set on the pod template to restart the pods of a workload, like `kubectl rollout restart`.
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// restartPatch sets RestartedAtAnnotation on the pod template.
+func restartPatch(now time.Time) []byte {
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{RestartedAtAnnotation: now.Format(time.RFC3339)},
+				},
+			},
+		},
+	}
+
+	data, _ := json.Marshal(patch)
+
+	return data
+}
+
+func newRestartError(resource string, err error) error {
+	return fmt.Errorf("error restarting %s: %w", resource, err)
+}
+
+// RestartDeployment triggers a rollout of the deployment with the given name.
+func (appsv1 *AppsV1) RestartDeployment(resource string, now time.Time) error {
+	_, err := appsv1.
+		AppsV1Interface.
+		Deployments(appsv1.options.Namespace).
+		Patch(context.TODO(), resource, types.StrategicMergePatchType, restartPatch(now), metav1.PatchOptions{})
+
+	if err != nil {
+		return newRestartError("Deployment", err)
+	}
+
+	return nil
+}
+
+// RestartDaemonSet triggers a rollout of the daemonset with the given name.
+func (appsv1 *AppsV1) RestartDaemonSet(resource string, now time.Time) error {
+	_, err := appsv1.
+		AppsV1Interface.
+		DaemonSets(appsv1.options.Namespace).
+		Patch(context.TODO(), resource, types.StrategicMergePatchType, restartPatch(now), metav1.PatchOptions{})
+
+	if err != nil {
+		return newRestartError("DaemonSet", err)
+	}
+
+	return nil
+}
+
+// RestartStatefulSet triggers a rollout of the statefulset with the given name.
+func (appsv1 *AppsV1) RestartStatefulSet(resource string, now time.Time) error {
+	_, err := appsv1.
+		AppsV1Interface.
+		StatefulSets(appsv1.options.Namespace).
+		Patch(context.TODO(), resource, types.StrategicMergePatchType, restartPatch(now), metav1.PatchOptions{})
+
+	if err != nil {
+		return newRestartError("StatefulSet", err)
+	}
+
+	return nil
+}
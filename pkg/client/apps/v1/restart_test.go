@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAppsV1_restart(t *testing.T) {
+	now := time.Date(2022, 1, 31, 15, 4, 5, 0, time.UTC)
+	kubeClient := mock.NewFakeClient(
+		mock.Deployment("test", "test", nil, nil, nil),
+		mock.DaemonSet("test", "test", nil, nil, nil),
+		mock.StatefulSet("test", "test", nil, nil, nil),
+	)
+	appsv1 := NewAppsV1(kubeClient, &options.Client{Namespace: "test"})
+
+	tests := []struct {
+		name     string
+		restart  func(resource string, now time.Time) error
+		template func() (*corev1.PodTemplateSpec, error)
+	}{
+		{
+			name:    "deployment",
+			restart: appsv1.RestartDeployment,
+			template: func() (*corev1.PodTemplateSpec, error) {
+				resp, err := kubeClient.AppsV1().Deployments("test").Get(context.TODO(), "test", metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+
+				return &resp.Spec.Template, nil
+			},
+		},
+		{
+			name:    "daemonset",
+			restart: appsv1.RestartDaemonSet,
+			template: func() (*corev1.PodTemplateSpec, error) {
+				resp, err := kubeClient.AppsV1().DaemonSets("test").Get(context.TODO(), "test", metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+
+				return &resp.Spec.Template, nil
+			},
+		},
+		{
+			name:    "statefulset",
+			restart: appsv1.RestartStatefulSet,
+			template: func() (*corev1.PodTemplateSpec, error) {
+				resp, err := kubeClient.AppsV1().StatefulSets("test").Get(context.TODO(), "test", metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+
+				return &resp.Spec.Template, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.restart("test", now); err != nil {
+				t.Fatalf("restart error = %v", err)
+			}
+
+			template, err := tt.template()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := template.Annotations[RestartedAtAnnotation]; got != "2022-01-31T15:04:05Z" {
+				t.Errorf("restart annotation = %q, want %q", got, "2022-01-31T15:04:05Z")
+			}
+
+			if err := tt.restart("missing", now); err == nil {
+				t.Errorf("restart error = nil, want error")
+			}
+		})
+	}
+}
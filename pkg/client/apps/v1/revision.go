@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	appsapi "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ErrMissingRevision is returned when a workload has no revision with the requested number.
+var ErrMissingRevision = errors.New("revision not found")
+
+// revisionAnnotation holds the revision of the ReplicaSets owned by a Deployment.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// Revision is a version of the pod template of a workload, kept by Kubernetes to roll back.
+type Revision struct {
+	// Number is the revision number, the highest is the current revision.
+	Number int64
+	// Name is the name of the ReplicaSet or ControllerRevision holding the revision.
+	Name string
+	// Created is when the revision was created.
+	Created time.Time
+	// Workload is a copy of the workload with the pod template of the revision.
+	Workload runtime.Object
+}
+
+// listOptions selects the objects matching the selector of a workload.
+func listOptions(selector *metav1.LabelSelector) (metav1.ListOptions, error) {
+	if selector == nil {
+		return metav1.ListOptions{}, nil
+	}
+
+	labels, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return metav1.ListOptions{}, fmt.Errorf("parsing selector: %w", err)
+	}
+
+	return metav1.ListOptions{LabelSelector: labels.String()}, nil
+}
+
+// ownedBy reports whether obj is controlled by the owner of the given kind.
+func ownedBy(obj metav1.Object, kind string, owner metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == kind && ref.Name == owner.GetName() && ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortRevisions(revisions []Revision) []Revision {
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Number < revisions[j].Number })
+
+	return revisions
+}
+
+// findRevision returns the revision with the given number.
+func findRevision(revisions []Revision, number int64) (runtime.Object, error) {
+	for _, revision := range revisions {
+		if revision.Number == number {
+			return revision.Workload, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrMissingRevision, number)
+}
+
+// deploymentRevisions returns the revisions of a deployment, oldest first, from the ReplicaSets it owns.
+func (appsv1 *AppsV1) deploymentRevisions(deployment *appsapi.Deployment) ([]Revision, error) {
+	opts, err := listOptions(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := appsv1.
+		AppsV1Interface.
+		ReplicaSets(appsv1.options.Namespace).
+		List(context.TODO(), opts)
+
+	if err != nil {
+		return nil, NewResourceLoadError("ReplicaSets", err)
+	}
+
+	res := []Revision{}
+
+	for i := range resp.Items {
+		replicaSet := &resp.Items[i]
+		if !ownedBy(replicaSet, "Deployment", deployment) {
+			continue
+		}
+
+		number, err := strconv.ParseInt(replicaSet.Annotations[revisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		workload := deployment.DeepCopy()
+		workload.Spec.Template = *replicaSet.Spec.Template.DeepCopy()
+
+		res = append(res, Revision{
+			Number:   number,
+			Name:     replicaSet.Name,
+			Created:  replicaSet.CreationTimestamp.Time,
+			Workload: workload,
+		})
+	}
+
+	return sortRevisions(res), nil
+}
+
+// templatePatch is the content of the ControllerRevisions of StatefulSets and DaemonSets.
+type templatePatch struct {
+	Spec struct {
+		Template corev1.PodTemplateSpec `json:"template"`
+	} `json:"spec"`
+}
+
+// controllerRevisions returns the revisions of a StatefulSet or DaemonSet, oldest first, from the
+// ControllerRevisions it owns, apply copies the workload with the pod template of a revision.
+func (appsv1 *AppsV1) controllerRevisions(
+	kind string,
+	owner metav1.Object,
+	selector *metav1.LabelSelector,
+	apply func(template corev1.PodTemplateSpec) runtime.Object,
+) ([]Revision, error) {
+	opts, err := listOptions(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := appsv1.
+		AppsV1Interface.
+		ControllerRevisions(appsv1.options.Namespace).
+		List(context.TODO(), opts)
+
+	if err != nil {
+		return nil, NewResourceLoadError("ControllerRevisions", err)
+	}
+
+	res := []Revision{}
+
+	for i := range resp.Items {
+		revision := &resp.Items[i]
+		if !ownedBy(revision, kind, owner) {
+			continue
+		}
+
+		var patch templatePatch
+		if err := json.Unmarshal(revision.Data.Raw, &patch); err != nil {
+			return nil, NewResourceLoadError("ControllerRevision "+revision.Name, err)
+		}
+
+		res = append(res, Revision{
+			Number:   revision.Revision,
+			Name:     revision.Name,
+			Created:  revision.CreationTimestamp.Time,
+			Workload: apply(patch.Spec.Template),
+		})
+	}
+
+	return sortRevisions(res), nil
+}
+
+func (appsv1 *AppsV1) daemonSetRevisions(daemonSet *appsapi.DaemonSet) ([]Revision, error) {
+	return appsv1.controllerRevisions("DaemonSet", daemonSet, daemonSet.Spec.Selector,
+		func(template corev1.PodTemplateSpec) runtime.Object {
+			workload := daemonSet.DeepCopy()
+			workload.Spec.Template = template
+
+			return workload
+		})
+}
+
+func (appsv1 *AppsV1) statefulSetRevisions(statefulSet *appsapi.StatefulSet) ([]Revision, error) {
+	return appsv1.controllerRevisions("StatefulSet", statefulSet, statefulSet.Spec.Selector,
+		func(template corev1.PodTemplateSpec) runtime.Object {
+			workload := statefulSet.DeepCopy()
+			workload.Spec.Template = template
+
+			return workload
+		})
+}
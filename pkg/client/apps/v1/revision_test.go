@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func revisionNumbers(revisions []Revision) []int64 {
+	res := []int64{}
+	for _, revision := range revisions {
+		res = append(res, revision.Number)
+	}
+
+	return res
+}
+
+func TestAppsV1_revisions(t *testing.T) {
+	deployment := mock.Deployment("test", "test", map[string]string{"k": "v3"}, nil, nil)
+	other := mock.Deployment("other", "test", nil, nil, nil)
+	daemonSet := mock.DaemonSet("test", "test", map[string]string{"k": "v2"}, nil, nil)
+	statefulSet := mock.StatefulSet("test", "test", map[string]string{"k": "v2"}, nil, nil)
+	kubeClient := mock.NewFakeClient(
+		deployment, other, daemonSet, statefulSet,
+		mock.DeploymentRevision(deployment, 3, map[string]string{"k": "v3"}),
+		mock.DeploymentRevision(deployment, 1, map[string]string{"k": "v1"}),
+		mock.DeploymentRevision(other, 2, nil),
+		mock.ControllerRevision("DaemonSet", daemonSet, 2, map[string]string{"k": "v2"}),
+		mock.ControllerRevision("DaemonSet", daemonSet, 1, map[string]string{"k": "v1"}),
+		mock.ControllerRevision("StatefulSet", statefulSet, 1, map[string]string{"k": "v1"}),
+	)
+	errorClient := mock.NewFakeClient(deployment, daemonSet).
+		PrependReactor("list", "replicasets", true, nil, mock.AnError).
+		PrependReactor("list", "controllerrevisions", true, nil, mock.AnError)
+
+	revisions := func(appsv1 *AppsV1, kind, name string) ([]Revision, error) {
+		switch kind {
+		case "DaemonSet":
+			return appsv1.DaemonSetRevisions(name)
+		case "StatefulSet":
+			return appsv1.StatefulSetRevisions(name)
+		}
+
+		return appsv1.DeploymentRevisions(name)
+	}
+
+	tests := []struct {
+		name     string
+		kind     string
+		resource string
+		client   *mock.FakeClient
+		want     []int64
+		wantErr  bool
+	}{
+		{name: "deployment", kind: "Deployment", resource: "test", client: kubeClient, want: []int64{1, 3}},
+		{name: "daemonset", kind: "DaemonSet", resource: "test", client: kubeClient, want: []int64{1, 2}},
+		{name: "statefulset", kind: "StatefulSet", resource: "test", client: kubeClient, want: []int64{1}},
+		{name: "missing deployment", kind: "Deployment", resource: "missing", client: kubeClient, wantErr: true},
+		{name: "missing statefulset", kind: "StatefulSet", resource: "missing", client: kubeClient, wantErr: true},
+		{name: "replicaset list error", kind: "Deployment", resource: "test", client: errorClient, wantErr: true},
+		{name: "controller revision list error", kind: "DaemonSet", resource: "test", client: errorClient, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := revisions(NewAppsV1(tt.client, &options.Client{Namespace: "test"}), tt.kind, tt.resource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AppsV1 %s revisions error = %v, wantErr %v", tt.kind, err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !cmp.Equal(revisionNumbers(got), tt.want) {
+				t.Errorf("AppsV1 %s revisions = %v, want %v", tt.kind, revisionNumbers(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestAppsV1_revision(t *testing.T) {
+	deployment := mock.Deployment("test", "test", map[string]string{"k": "v2"}, nil, nil)
+	daemonSet := mock.DaemonSet("test", "test", map[string]string{"k": "v2"}, nil, nil)
+	statefulSet := mock.StatefulSet("test", "test", map[string]string{"k": "v2"}, nil, nil)
+	kubeClient := mock.NewFakeClient(
+		deployment, daemonSet, statefulSet,
+		mock.DeploymentRevision(deployment, 1, map[string]string{"k": "v1"}),
+		mock.ControllerRevision("DaemonSet", daemonSet, 1, map[string]string{"k": "v1"}),
+		mock.ControllerRevision("StatefulSet", statefulSet, 1, map[string]string{"k": "v1"}),
+	)
+	errorClient := mock.NewFakeClient(statefulSet).PrependReactor("list", "controllerrevisions", true, nil, mock.AnError)
+
+	tests := []struct {
+		name     string
+		revision int64
+		get      func(appsv1 *AppsV1) *result.Result
+		want     result.EnvValues
+		wantErr  error
+	}{
+		{name: "deployment", revision: 1, get: func(a *AppsV1) *result.Result { return a.Deployment("test") }},
+		{name: "daemonset", revision: 1, get: func(a *AppsV1) *result.Result { return a.DaemonSet("test") }},
+		{name: "statefulset", revision: 1, get: func(a *AppsV1) *result.Result { return a.StatefulSet("test") }},
+		{
+			name:     "missing revision",
+			revision: 5,
+			get:      func(a *AppsV1) *result.Result { return a.Deployment("test") },
+			wantErr:  ErrMissingRevision,
+		},
+		{
+			name:     "revisions error",
+			revision: 1,
+			get:      func(a *AppsV1) *result.Result { return NewAppsV1(errorClient, a.options).StatefulSet("test") },
+			wantErr:  mock.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.get(NewAppsV1(kubeClient, &options.Client{Namespace: "test", Revision: tt.revision}))
+			if !errors.Is(got.Error, tt.wantErr) {
+				t.Fatalf("AppsV1 revision error = %v, wantErr %v", got.Error, tt.wantErr)
+			}
+
+			if want := (result.EnvValues{"k": "v1"}); tt.wantErr == nil && !cmp.Equal(got.Environment, want) {
+				t.Errorf("AppsV1 revision = %v, want %v", got.Environment, want)
+			}
+		})
+	}
+}
+
+func Test_listOptions(t *testing.T) {
+	got, err := listOptions(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}})
+	if err != nil || got.LabelSelector != "app=api" {
+		t.Errorf("listOptions() = %v, %v, want app=api", got, err)
+	}
+
+	invalid := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "bad"}}}
+	if _, err := listOptions(invalid); err == nil {
+		t.Errorf("listOptions() error = nil, want error")
+	}
+}
+
+func Test_controllerRevisions_invalidData(t *testing.T) {
+	daemonSet := mock.DaemonSet("test", "test", nil, nil, nil)
+	revision := mock.ControllerRevision("DaemonSet", daemonSet, 1, nil)
+	revision.Data = runtime.RawExtension{Raw: []byte("{")}
+
+	appsv1 := NewAppsV1(mock.NewFakeClient(daemonSet, revision), &options.Client{Namespace: "test"})
+	if _, err := appsv1.DaemonSetRevisions("test"); err == nil {
+		t.Errorf("AppsV1.DaemonSetRevisions() error = nil, want error")
+	}
+}
@@ -18,11 +18,10 @@ func (batchv1 *BatchV1) Job(resource string) *result.Result {
 		return result.NewFromError(NewResourceLoadError("Job", err))
 	}
 
-	return result.NewFromContainers(
+	return result.NewFromWorkload(
 		batchv1.kubeClient,
-		batchv1.options.Namespace,
-		batchv1.options.ShouldExport,
-		resp.Spec.Template.Spec.Containers,
+		batchv1.options,
+		resp,
 	)
 }
 
@@ -18,11 +18,10 @@ func (batchv1beta1 *BatchV1Beta1) CronJob(resource string) *result.Result {
 		return result.NewFromError(NewResourceLoadError("CronJob", err))
 	}
 
-	return result.NewFromContainers(
+	return result.NewFromWorkload(
 		batchv1beta1.kubeClient,
-		batchv1beta1.options.Namespace,
-		batchv1beta1.options.ShouldExport,
-		resp.Spec.JobTemplate.Spec.Template.Spec.Containers,
+		batchv1beta1.options,
+		resp,
 	)
 }
 
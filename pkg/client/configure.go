@@ -5,6 +5,7 @@ import (
 	batchv1 "github.com/eiladin/k8s-dotenv/pkg/client/batch/v1"
 	batchv1beta1 "github.com/eiladin/k8s-dotenv/pkg/client/batch/v1beta1"
 	corev1 "github.com/eiladin/k8s-dotenv/pkg/client/core/v1"
+	"github.com/eiladin/k8s-dotenv/pkg/options"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -32,3 +33,25 @@ func WithNamespace(namespace string) ConfigureFunc {
 		client.options.Namespace = namespace
 	}
 }
+
+// WithOutput sets the format and rendering options used when writing results.
+func WithOutput(output options.Output) ConfigureFunc {
+	return func(client *Client) {
+		client.options.Output = output
+	}
+}
+
+// WithContainer limits results to the container with the given name.
+func WithContainer(container string) ConfigureFunc {
+	return func(client *Client) {
+		client.options.Container = container
+	}
+}
+
+// WithRevision loads the pod template of a past revision of deployments, daemonsets and statefulsets,
+// the current one is used when revision is 0.
+func WithRevision(revision int64) ConfigureFunc {
+	return func(client *Client) {
+		client.options.Revision = revision
+	}
+}
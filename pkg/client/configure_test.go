@@ -108,3 +108,105 @@ func TestWithNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestWithOutput(t *testing.T) {
+	type args struct {
+		output options.Output
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *Client
+	}{
+		{
+			name: "update Client Output",
+			args: args{output: options.Output{Format: "systemd"}},
+			want: &Client{options: &options.Client{Output: options.Output{Format: "systemd"}}},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			fn := WithOutput(testCase.args.output)
+			got := NewClient()
+			fn(got)
+
+			opt := []cmp.Option{
+				cmp.AllowUnexported(Client{}),
+			}
+
+			if !cmp.Equal(got, testCase.want, opt...) {
+				t.Errorf("WithOutput() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestWithContainer(t *testing.T) {
+	type args struct {
+		container string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *Client
+	}{
+		{
+			name: "update Client Container",
+			args: args{container: "api"},
+			want: &Client{options: &options.Client{Container: "api"}},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			fn := WithContainer(testCase.args.container)
+			got := NewClient()
+			fn(got)
+
+			opt := []cmp.Option{
+				cmp.AllowUnexported(Client{}),
+			}
+
+			if !cmp.Equal(got, testCase.want, opt...) {
+				t.Errorf("WithContainer() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestWithRevision(t *testing.T) {
+	type args struct {
+		revision int64
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *Client
+	}{
+		{
+			name: "update Client Revision",
+			args: args{revision: 3},
+			want: &Client{options: &options.Client{Revision: 3}},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			fn := WithRevision(testCase.args.revision)
+			got := NewClient()
+			fn(got)
+
+			opt := []cmp.Option{
+				cmp.AllowUnexported(Client{}),
+			}
+
+			if !cmp.Equal(got, testCase.want, opt...) {
+				t.Errorf("WithRevision() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
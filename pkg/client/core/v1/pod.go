@@ -18,11 +18,10 @@ func (corev1 *CoreV1) Pod(resource string) *result.Result {
 		return result.NewFromError(NewResourceLoadError("Pod", err))
 	}
 
-	return result.NewFromContainers(
+	return result.NewFromWorkload(
 		corev1.kubeClient,
-		corev1.options.Namespace,
-		corev1.options.ShouldExport,
-		resp.Spec.Containers,
+		corev1.options,
+		resp,
 	)
 }
 
@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrConflict is returned when a ConfigMap or Secret was changed since it was read.
+var ErrConflict = errors.New("resource was changed since it was read")
+
+// Source is the data of a ConfigMap or Secret with the resourceVersion it was read at.
+type Source struct {
+	Name            string
+	Data            map[string]string
+	ResourceVersion string
+}
+
+// SourcePatch changes some keys of a ConfigMap or Secret read at ResourceVersion, other keys are kept.
+type SourcePatch struct {
+	Name            string
+	Set             map[string]string
+	Delete          []string
+	ResourceVersion string
+}
+
+func newUpdateError(resource string, err error) error {
+	if apierrors.IsConflict(err) {
+		return fmt.Errorf("%w: %s", ErrConflict, resource)
+	}
+
+	return fmt.Errorf("error updating %s: %w", resource, err)
+}
+
+// ConfigMapSource returns the data of a config map with its resourceVersion.
+func (corev1 *CoreV1) ConfigMapSource(resource string) (*Source, error) {
+	resp, err := corev1.
+		ConfigMaps(corev1.options.Namespace).
+		Get(context.TODO(), resource, metav1.GetOptions{})
+
+	if err != nil {
+		return nil, NewResourceLoadError("ConfigMap "+resource, err)
+	}
+
+	return &Source{Name: resource, Data: resp.Data, ResourceVersion: resp.ResourceVersion}, nil
+}
+
+// UpdateConfigMap sets and deletes the keys of patch in a config map, failing with `ErrConflict` when it was changed
+// since it was read.
+func (corev1 *CoreV1) UpdateConfigMap(patch *SourcePatch) error {
+	resp, err := corev1.
+		ConfigMaps(corev1.options.Namespace).
+		Get(context.TODO(), patch.Name, metav1.GetOptions{})
+
+	if err != nil {
+		return NewResourceLoadError("ConfigMap "+patch.Name, err)
+	}
+
+	if resp.ResourceVersion != patch.ResourceVersion {
+		return fmt.Errorf("%w: configmap %s", ErrConflict, patch.Name)
+	}
+
+	if resp.Data == nil {
+		resp.Data = map[string]string{}
+	}
+
+	for k, v := range patch.Set {
+		resp.Data[k] = v
+	}
+
+	for _, k := range patch.Delete {
+		delete(resp.Data, k)
+	}
+
+	_, err = corev1.
+		ConfigMaps(corev1.options.Namespace).
+		Update(context.TODO(), resp, metav1.UpdateOptions{})
+
+	if err != nil {
+		return newUpdateError("configmap "+patch.Name, err)
+	}
+
+	return nil
+}
+
+// SecretSource returns the data of a secret with its resourceVersion.
+func (corev1 *CoreV1) SecretSource(resource string) (*Source, error) {
+	resp, err := corev1.
+		Secrets(corev1.options.Namespace).
+		Get(context.TODO(), resource, metav1.GetOptions{})
+
+	if err != nil {
+		return nil, NewResourceLoadError("Secret "+resource, err)
+	}
+
+	data := make(map[string]string, len(resp.Data))
+	for k, v := range resp.Data {
+		data[k] = string(v)
+	}
+
+	return &Source{Name: resource, Data: data, ResourceVersion: resp.ResourceVersion}, nil
+}
+
+// UpdateSecret sets and deletes the keys of patch in a secret, failing with `ErrConflict` when it was changed
+// since it was read.
+func (corev1 *CoreV1) UpdateSecret(patch *SourcePatch) error {
+	resp, err := corev1.
+		Secrets(corev1.options.Namespace).
+		Get(context.TODO(), patch.Name, metav1.GetOptions{})
+
+	if err != nil {
+		return NewResourceLoadError("Secret "+patch.Name, err)
+	}
+
+	if resp.ResourceVersion != patch.ResourceVersion {
+		return fmt.Errorf("%w: secret %s", ErrConflict, patch.Name)
+	}
+
+	if resp.Data == nil {
+		resp.Data = map[string][]byte{}
+	}
+
+	for k, v := range patch.Set {
+		resp.Data[k] = []byte(v)
+	}
+
+	for _, k := range patch.Delete {
+		delete(resp.Data, k)
+	}
+
+	resp.StringData = nil
+
+	_, err = corev1.
+		Secrets(corev1.options.Namespace).
+		Update(context.TODO(), resp, metav1.UpdateOptions{})
+
+	if err != nil {
+		return newUpdateError("secret "+patch.Name, err)
+	}
+
+	return nil
+}
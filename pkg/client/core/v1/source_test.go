@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCoreV1_sources(t *testing.T) {
+	configMap := mock.ConfigMap("test", "test", map[string]string{"k": "v", "other": "o"})
+	configMap.ResourceVersion = "1"
+	secret := mock.Secret("test", "test", map[string][]byte{"k": []byte("v"), "other": []byte("o")})
+	secret.ResourceVersion = "1"
+	corev1 := NewCoreV1(mock.NewFakeClient(configMap, secret), &options.Client{Namespace: "test"})
+
+	for _, kind := range []string{"ConfigMap", "Secret"} {
+		t.Run(kind, func(t *testing.T) {
+			read, update := corev1.ConfigMapSource, corev1.UpdateConfigMap
+			if kind == "Secret" {
+				read, update = corev1.SecretSource, corev1.UpdateSecret
+			}
+
+			got, err := read("test")
+			if err != nil {
+				t.Fatalf("CoreV1.%sSource() error = %v", kind, err)
+			}
+
+			want := &Source{Name: "test", Data: map[string]string{"k": "v", "other": "o"}, ResourceVersion: "1"}
+			if !cmp.Equal(got, want) {
+				t.Errorf("CoreV1.%sSource() = %v", kind, cmp.Diff(want, got))
+			}
+
+			if _, err := read("missing"); err == nil {
+				t.Errorf("CoreV1.%sSource() error = nil, want error", kind)
+			}
+
+			stale := &SourcePatch{Name: "test", Set: map[string]string{"k": "stale"}, ResourceVersion: "0"}
+			if err := update(stale); !errors.Is(err, ErrConflict) {
+				t.Errorf("CoreV1.Update%s() error = %v, want %v", kind, err, ErrConflict)
+			}
+
+			if err := update(&SourcePatch{Name: "missing"}); err == nil {
+				t.Errorf("CoreV1.Update%s() error = nil, want error", kind)
+			}
+
+			patch := &SourcePatch{
+				Name:            "test",
+				Set:             map[string]string{"k": "v2", "k2": "new"},
+				Delete:          []string{"other"},
+				ResourceVersion: got.ResourceVersion,
+			}
+			if err := update(patch); err != nil {
+				t.Fatalf("CoreV1.Update%s() error = %v", kind, err)
+			}
+
+			wantData := map[string]string{"k": "v2", "k2": "new"}
+			if updated, _ := read("test"); !cmp.Equal(updated.Data, wantData) {
+				t.Errorf("CoreV1.Update%s() data = %v, want %v", kind, updated.Data, wantData)
+			}
+		})
+	}
+}
+
+func TestCoreV1_UpdateConfigMap_conflict(t *testing.T) {
+	conflict := apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "test", mock.AnError)
+	kubeClient := mock.NewFakeClient(mock.ConfigMap("test", "test", nil)).
+		PrependReactor("update", "configmaps", true, nil, conflict).
+		PrependReactor("update", "secrets", true, nil, mock.AnError)
+	corev1 := NewCoreV1(kubeClient, &options.Client{Namespace: "test"})
+
+	if err := corev1.UpdateConfigMap(&SourcePatch{Name: "test"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("CoreV1.UpdateConfigMap() error = %v, want %v", err, ErrConflict)
+	}
+
+	kubeClient = mock.NewFakeClient(mock.Secret("test", "test", nil)).PrependReactor("update", "secrets", true, nil, mock.AnError)
+	corev1 = NewCoreV1(kubeClient, &options.Client{Namespace: "test"})
+
+	if err := corev1.UpdateSecret(&SourcePatch{Name: "test"}); !errors.Is(err, mock.AnError) {
+		t.Errorf("CoreV1.UpdateSecret() error = %v, want %v", err, mock.AnError)
+	}
+}
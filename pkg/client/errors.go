@@ -11,6 +11,15 @@ var ErrMissingResource = errors.New("resource not found")
 // ErrAPIGroup is returned when a kubernetes api call fails.
 var ErrAPIGroup = errors.New("api group error")
 
+// ErrInvalidResource is returned when a resource reference is not in the `TYPE/NAME` form.
+var ErrInvalidResource = errors.New("resource must be in the form TYPE/NAME")
+
+// ErrUnsupportedType is returned when a resource type is not supported.
+var ErrUnsupportedType = errors.New("resource type not supported")
+
+// ErrUnsupportedGroup is returned when a group/resource combination is invalid.
+var ErrUnsupportedGroup = errors.New("group/resource not supported")
+
 func newMissingKubeClientError(client string) error {
 	//nolint
 	return fmt.Errorf("could not create %s client, missing call to WithKubeClient?", client)
@@ -0,0 +1,262 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// kindWorkload identifies events of the watched workload.
+const kindWorkload = "WORKLOAD"
+
+// watchEvent is the kind and name of an object that was added, updated or deleted.
+type watchEvent struct {
+	kind string
+	name string
+}
+
+// eventHandler sends the name of every changed object to events until ctx is done.
+func eventHandler(ctx context.Context, events chan<- watchEvent, kind string) cache.ResourceEventHandler {
+	send := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+
+		object, ok := obj.(metav1.Object)
+		if !ok {
+			return
+		}
+
+		select {
+		case events <- watchEvent{kind: kind, name: object.GetName()}:
+		case <-ctx.Done():
+		}
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    send,
+		UpdateFunc: func(_, obj interface{}) { send(obj) },
+		DeleteFunc: send,
+	}
+}
+
+// workloadInformer returns the informer for a resource type, which can be any of the aliases used by `get`.
+func (client *Client) workloadInformer(factory informers.SharedInformerFactory, resourceType string) (cache.SharedIndexInformer, error) {
+	switch strings.ToLower(resourceType) {
+	case "cronjob", "cronjobs", "cj":
+		group, err := client.GetAPIGroup("CronJob")
+		if err != nil {
+			return nil, err
+		}
+
+		switch group {
+		case "batch/v1beta1":
+			return factory.Batch().V1beta1().CronJobs().Informer(), nil
+		case "batch/v1":
+			return factory.Batch().V1().CronJobs().Informer(), nil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedGroup, group)
+	case "daemonset", "daemonsets", "ds":
+		return factory.Apps().V1().DaemonSets().Informer(), nil
+	case "deployment", "deployments", "deploy":
+		return factory.Apps().V1().Deployments().Informer(), nil
+	case "job", "jobs":
+		return factory.Batch().V1().Jobs().Informer(), nil
+	case "pod", "pods", "po":
+		return factory.Core().V1().Pods().Informer(), nil
+	case "replicaset", "replicasets", "rs":
+		return factory.Apps().V1().ReplicaSets().Informer(), nil
+	case "statefulset", "statefulsets", "sts":
+		return factory.Apps().V1().StatefulSets().Informer(), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, resourceType)
+}
+
+// nameSelector restricts the list and watch calls of an informer to the object called name.
+func nameSelector(name string) informers.SharedInformerOption {
+	return informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+		listOptions.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	})
+}
+
+// sourceWatch runs an informer for each watched ConfigMap and Secret, so only the sources of the workload are
+// cached rather than every ConfigMap and Secret of the namespace.
+type sourceWatch struct {
+	client *Client
+	events chan<- watchEvent
+	stops  map[watchEvent]context.CancelFunc
+}
+
+func newSourceWatch(client *Client, events chan<- watchEvent) *sourceWatch {
+	return &sourceWatch{client: client, events: events, stops: map[watchEvent]context.CancelFunc{}}
+}
+
+// set starts the informers of sources that are not watched yet until ctx is done, and stops the ones of sources
+// no longer listed.
+func (watch *sourceWatch) set(ctx context.Context, sources map[watchEvent]bool) {
+	for source, stop := range watch.stops {
+		if !sources[source] {
+			stop()
+			delete(watch.stops, source)
+		}
+	}
+
+	for source := range sources {
+		if _, found := watch.stops[source]; found {
+			continue
+		}
+
+		ctx, stop := context.WithCancel(ctx)
+		factory := informers.NewSharedInformerFactoryWithOptions(watch.client.Interface, 0,
+			informers.WithNamespace(watch.client.options.Namespace), nameSelector(source.name))
+
+		informer := factory.Core().V1().ConfigMaps().Informer()
+		if source.kind == result.KindSecret {
+			informer = factory.Core().V1().Secrets().Informer()
+		}
+
+		informer.AddEventHandler(eventHandler(ctx, watch.events, source.kind))
+		factory.Start(ctx.Done())
+		factory.WaitForCacheSync(ctx.Done())
+
+		watch.stops[source] = stop
+	}
+}
+
+// referencedSources returns the ConfigMaps and Secrets loaded in res and the ones referenced by the cached
+// workloads, so a source that is missing or was added to the workload is watched as well.
+func (client *Client) referencedSources(workloads []interface{}, res *result.Result) map[watchEvent]bool {
+	sources := map[watchEvent]bool{}
+
+	for name := range res.ConfigMaps {
+		sources[watchEvent{kind: result.KindConfigMap, name: name}] = true
+	}
+
+	for name := range res.Secrets {
+		sources[watchEvent{kind: result.KindSecret, name: name}] = true
+	}
+
+	for _, obj := range workloads {
+		if workload, ok := obj.(runtime.Object); ok {
+			client.addSpecSources(sources, result.PodSpec(workload))
+		}
+	}
+
+	return sources
+}
+
+// addSpecSources adds the ConfigMaps and Secrets the containers of spec load with envFrom to sources.
+func (client *Client) addSpecSources(sources map[watchEvent]bool, spec *corev1.PodSpec) {
+	if spec == nil {
+		return
+	}
+
+	for _, container := range spec.Containers {
+		if client.options.Container != "" && container.Name != client.options.Container {
+			continue
+		}
+
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				sources[watchEvent{kind: result.KindConfigMap, name: envFrom.ConfigMapRef.Name}] = true
+			}
+
+			if envFrom.SecretRef != nil {
+				sources[watchEvent{kind: result.KindSecret, name: envFrom.SecretRef.Name}] = true
+			}
+		}
+	}
+}
+
+// affects reports whether an event can change the result of the workload name.
+//
+// Every watched ConfigMap and Secret affects a result with an error, since a missing source may have been created.
+func affects(res *result.Result, name string, event watchEvent) bool {
+	switch event.kind {
+	case kindWorkload:
+		return event.name == name
+	case result.KindConfigMap:
+		_, found := res.ConfigMaps[event.name]
+
+		return found || res.Error != nil
+	case result.KindSecret:
+		_, found := res.Secrets[event.name]
+
+		return found || res.Error != nil
+	}
+
+	return false
+}
+
+// Watch calls update with the result of a workload, then again whenever the workload or one of its ConfigMaps or
+// Secrets changes, until ctx is done or update returns an error. Only the workload and the sources it references
+// are watched, each with a field selector on its name.
+//
+// Events received while the workload is resolved are handled together, so a burst of changes resolves it once.
+func (client *Client) Watch(ctx context.Context, resourceType, name string, update func(res *result.Result) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workloads := informers.NewSharedInformerFactoryWithOptions(client.Interface, 0,
+		informers.WithNamespace(client.options.Namespace), nameSelector(name))
+
+	workload, err := client.workloadInformer(workloads, resourceType)
+	if err != nil {
+		return err
+	}
+
+	events := make(chan watchEvent)
+
+	workload.AddEventHandler(eventHandler(ctx, events, kindWorkload))
+
+	workloads.Start(ctx.Done())
+	workloads.WaitForCacheSync(ctx.Done())
+
+	sources := newSourceWatch(client, events)
+
+	res := client.Workload(resourceType, name)
+	sources.set(ctx, client.referencedSources(workload.GetStore().List(), res))
+
+	if err := update(res); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-events:
+			changed := affects(res, name, event)
+
+			for pending := true; pending; {
+				select {
+				case event := <-events:
+					changed = changed || affects(res, name, event)
+				default:
+					pending = false
+				}
+			}
+
+			if !changed {
+				continue
+			}
+
+			res = client.Workload(resourceType, name)
+			sources.set(ctx, client.referencedSources(workload.GetStore().List(), res))
+
+			if err := update(res); err != nil {
+				return err
+			}
+		}
+	}
+}
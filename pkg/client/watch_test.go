@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+)
+
+func Test_affects(t *testing.T) {
+	res := &result.Result{
+		ConfigMaps: map[string]result.EnvValues{"cm": {}},
+		Secrets:    map[string]result.EnvValues{"sec": {}},
+	}
+	failed := result.NewFromError(mock.AnError)
+
+	tests := []struct {
+		name  string
+		res   *result.Result
+		event watchEvent
+		want  bool
+	}{
+		{name: "workload", res: res, event: watchEvent{kind: kindWorkload, name: "test"}, want: true},
+		{name: "other workload", res: res, event: watchEvent{kind: kindWorkload, name: "other"}},
+		{name: "configmap", res: res, event: watchEvent{kind: result.KindConfigMap, name: "cm"}, want: true},
+		{name: "other configmap", res: res, event: watchEvent{kind: result.KindConfigMap, name: "other"}},
+		{name: "secret", res: res, event: watchEvent{kind: result.KindSecret, name: "sec"}, want: true},
+		{name: "other secret", res: res, event: watchEvent{kind: result.KindSecret, name: "other"}},
+		{name: "any source after an error", res: failed, event: watchEvent{kind: result.KindSecret, name: "other"}, want: true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := affects(testCase.res, "test", testCase.event); got != testCase.want {
+				t.Errorf("affects() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestClient_referencedSources(t *testing.T) {
+	workload := mock.Deployment("test", "test", nil, []string{"cm"}, []string{"missing"})
+	res := &result.Result{ConfigMaps: map[string]result.EnvValues{"loaded": {}}}
+
+	tests := []struct {
+		name      string
+		container string
+		workloads []interface{}
+		want      map[watchEvent]bool
+	}{
+		{
+			name:      "loaded and referenced",
+			workloads: []interface{}{workload, "not a workload"},
+			want: map[watchEvent]bool{
+				{kind: result.KindConfigMap, name: "loaded"}: true,
+				{kind: result.KindConfigMap, name: "cm"}:     true,
+				{kind: result.KindSecret, name: "missing"}:   true,
+			},
+		},
+		{
+			name:      "other container",
+			container: "other",
+			workloads: []interface{}{workload},
+			want:      map[watchEvent]bool{{kind: result.KindConfigMap, name: "loaded"}: true},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := NewClient(WithKubeClient(mock.NewFakeClient()), WithContainer(testCase.container))
+
+			if got := client.referencedSources(testCase.workloads, res); !cmp.Equal(got, testCase.want, cmp.AllowUnexported(watchEvent{})) {
+				t.Errorf("Client.referencedSources() = %v", cmp.Diff(testCase.want, got, cmp.AllowUnexported(watchEvent{})))
+			}
+		})
+	}
+}
+
+func Test_sourceWatch_set(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan watchEvent, 10)
+	kubeClient := mock.NewFakeClient(mock.ConfigMap("cm", "test", nil), mock.Secret("sec", "test", nil))
+	watch := newSourceWatch(NewClient(WithKubeClient(kubeClient), WithNamespace("test")), events)
+
+	configMap, secret := watchEvent{kind: result.KindConfigMap, name: "cm"}, watchEvent{kind: result.KindSecret, name: "sec"}
+
+	watch.set(ctx, map[watchEvent]bool{configMap: true, secret: true})
+
+	for seen := map[watchEvent]bool{}; len(seen) < 2; {
+		select {
+		case event := <-events:
+			seen[event] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("sourceWatch.set() events = %v, want %v and %v", seen, configMap, secret)
+		}
+	}
+
+	watch.set(ctx, map[watchEvent]bool{secret: true})
+
+	if _, found := watch.stops[configMap]; found || len(watch.stops) != 1 {
+		t.Errorf("sourceWatch.set() watched = %v, want only %v", watch.stops, secret)
+	}
+}
+
+func TestClient_workloadInformer(t *testing.T) {
+	kubeClient := mock.NewFakeClient().WithResources(mock.CronJobv1Resource())
+	beta1Client := mock.NewFakeClient().WithResources(mock.CronJobv1beta1Resource())
+	unsupportedClient := mock.NewFakeClient().WithResources(mock.UnsupportedGroupResource())
+
+	tests := []struct {
+		name         string
+		client       *Client
+		resourceType string
+		wantErr      bool
+	}{
+		{name: "cronjob", client: NewClient(WithKubeClient(kubeClient)), resourceType: "cj"},
+		{name: "cronjob v1beta1", client: NewClient(WithKubeClient(beta1Client)), resourceType: "cronjob"},
+		{name: "daemonset", client: NewClient(WithKubeClient(kubeClient)), resourceType: "ds"},
+		{name: "deployment", client: NewClient(WithKubeClient(kubeClient)), resourceType: "deploy"},
+		{name: "job", client: NewClient(WithKubeClient(kubeClient)), resourceType: "job"},
+		{name: "pod", client: NewClient(WithKubeClient(kubeClient)), resourceType: "po"},
+		{name: "replicaset", client: NewClient(WithKubeClient(kubeClient)), resourceType: "rs"},
+		{name: "statefulset", client: NewClient(WithKubeClient(kubeClient)), resourceType: "sts"},
+		{name: "error on unsupported group", client: NewClient(WithKubeClient(unsupportedClient)), resourceType: "cj", wantErr: true},
+		{name: "error on missing group", client: NewClient(WithKubeClient(mock.NewFakeClient())), resourceType: "cj", wantErr: true},
+		{name: "error on unsupported type", client: NewClient(WithKubeClient(kubeClient)), resourceType: "svc", wantErr: true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			factory := informers.NewSharedInformerFactory(testCase.client.Interface, 0)
+
+			got, err := testCase.client.workloadInformer(factory, testCase.resourceType)
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("Client.workloadInformer() error = %v, wantErr %v", err, testCase.wantErr)
+			}
+
+			if (got == nil) != testCase.wantErr {
+				t.Errorf("Client.workloadInformer() = %v, wantErr %v", got, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Watch(t *testing.T) {
+	kubeClient := mock.NewFakeClient(
+		mock.Deployment("test", "test", nil, []string{"cm"}, nil),
+		mock.ConfigMap("cm", "test", map[string]string{"k": "v1"}),
+	)
+	client := NewClient(WithKubeClient(kubeClient), WithNamespace("test"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make(chan *result.Result)
+	done := make(chan error)
+
+	go func() {
+		done <- client.Watch(ctx, "deploy", "test", func(res *result.Result) error {
+			results <- res
+
+			return nil
+		})
+	}()
+
+	if got := (<-results).ConfigMaps["cm"]["k"]; got != "v1" {
+		t.Fatalf("Client.Watch() first value = %q, want %q", got, "v1")
+	}
+
+	// The fake clientset drops events sent before its watch is started, so the change is repeated until it is seen.
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	timeout := time.After(5 * time.Second)
+
+	for seen := false; !seen; {
+		select {
+		case res := <-results:
+			seen = res.ConfigMaps["cm"]["k"] == "v2"
+		case <-ticker.C:
+			configMap := mock.ConfigMap("cm", "test", map[string]string{"k": "v2"})
+			if _, err := kubeClient.CoreV1().ConfigMaps("test").Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		case <-timeout:
+			t.Fatal("Client.Watch() did not see the configmap change")
+		}
+	}
+
+	cancel()
+
+	for {
+		select {
+		case <-results:
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Client.Watch() error = %v, want nil", err)
+			}
+
+			return
+		}
+	}
+}
+
+func TestClient_Watch_errors(t *testing.T) {
+	client := NewClient(WithKubeClient(mock.NewFakeClient()), WithNamespace("test"))
+
+	if err := client.Watch(context.Background(), "svc", "test", nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Client.Watch() error = %v, want %v", err, ErrUnsupportedType)
+	}
+
+	err := client.Watch(context.Background(), "deploy", "test", func(res *result.Result) error {
+		return mock.AnError
+	})
+	if !errors.Is(err, mock.AnError) {
+		t.Errorf("Client.Watch() error = %v, want %v", err, mock.AnError)
+	}
+}
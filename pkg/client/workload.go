@@ -0,0 +1,136 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "github.com/eiladin/k8s-dotenv/pkg/client/apps/v1"
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+)
+
+// ParseResource splits a `TYPE/NAME` resource reference such as `deploy/api`.
+func ParseResource(resource string) (string, string, error) {
+	parts := strings.SplitN(resource, "/", 2) //nolint
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidResource, resource)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// WorkloadTypes returns the supported resource types, including the aliases used by `get`.
+func WorkloadTypes() []string {
+	return []string{
+		"cronjob", "cronjobs", "cj",
+		"daemonset", "daemonsets", "ds",
+		"deployment", "deployments", "deploy",
+		"job", "jobs",
+		"pod", "pods", "po",
+		"replicaset", "replicasets", "rs",
+		"statefulset", "statefulsets", "sts",
+	}
+}
+
+// ResourceName returns `TYPE/NAME` with the type in the singular form, such as `deployment/api` for `deploy/api`,
+// so every alias of a type names the resource the same way. Unknown types are only lower cased.
+func ResourceName(resourceType, name string) string {
+	resourceType = strings.ToLower(resourceType)
+
+	switch resourceType {
+	case "cronjobs", "cj":
+		resourceType = "cronjob"
+	case "daemonsets", "ds":
+		resourceType = "daemonset"
+	case "deployments", "deploy":
+		resourceType = "deployment"
+	case "jobs":
+		resourceType = "job"
+	case "pods", "po":
+		resourceType = "pod"
+	case "replicasets", "rs":
+		resourceType = "replicaset"
+	case "statefulsets", "sts":
+		resourceType = "statefulset"
+	}
+
+	return resourceType + "/" + name
+}
+
+// Workload returns a single resource given its type, which can be any of the aliases used by `get`, and name.
+func (client *Client) Workload(resourceType, name string) *result.Result {
+	switch strings.ToLower(resourceType) {
+	case "cronjob", "cronjobs", "cj":
+		group, err := client.GetAPIGroup("CronJob")
+		if err != nil {
+			return result.NewFromError(err)
+		}
+
+		switch group {
+		case "batch/v1beta1":
+			return client.BatchV1Beta1().CronJob(name)
+		case "batch/v1":
+			return client.BatchV1().CronJob(name)
+		}
+
+		return result.NewFromError(fmt.Errorf("%w: %s", ErrUnsupportedGroup, group))
+	case "daemonset", "daemonsets", "ds":
+		return client.AppsV1().DaemonSet(name)
+	case "deployment", "deployments", "deploy":
+		return client.AppsV1().Deployment(name)
+	case "job", "jobs":
+		return client.BatchV1().Job(name)
+	case "pod", "pods", "po":
+		return client.CoreV1().Pod(name)
+	case "replicaset", "replicasets", "rs":
+		return client.AppsV1().ReplicaSet(name)
+	case "statefulset", "statefulsets", "sts":
+		return client.AppsV1().StatefulSet(name)
+	}
+
+	return result.NewFromError(fmt.Errorf("%w: %s", ErrUnsupportedType, resourceType))
+}
+
+// Revisions returns the revisions of a deployment, daemonset or statefulset, oldest first.
+func (client *Client) Revisions(resourceType, name string) ([]appsv1.Revision, error) {
+	switch strings.ToLower(resourceType) {
+	case "daemonset", "daemonsets", "ds":
+		//nolint
+		return client.AppsV1().DaemonSetRevisions(name)
+	case "deployment", "deployments", "deploy":
+		//nolint
+		return client.AppsV1().DeploymentRevisions(name)
+	case "statefulset", "statefulsets", "sts":
+		//nolint
+		return client.AppsV1().StatefulSetRevisions(name)
+	}
+
+	return nil, fmt.Errorf("%w: %s does not keep revisions", ErrUnsupportedType, resourceType)
+}
+
+// Restart triggers a rollout of a deployment, daemonset or statefulset by annotating its pod template.
+func (client *Client) Restart(resourceType, name string, now time.Time) error {
+	switch strings.ToLower(resourceType) {
+	case "daemonset", "daemonsets", "ds":
+		//nolint
+		return client.AppsV1().RestartDaemonSet(name, now)
+	case "deployment", "deployments", "deploy":
+		//nolint
+		return client.AppsV1().RestartDeployment(name, now)
+	case "statefulset", "statefulsets", "sts":
+		//nolint
+		return client.AppsV1().RestartStatefulSet(name, now)
+	}
+
+	return fmt.Errorf("%w: %s cannot be restarted", ErrUnsupportedType, resourceType)
+}
+
+// CanRestart reports whether a resource type is supported by `Restart`.
+func CanRestart(resourceType string) bool {
+	switch strings.ToLower(resourceType) {
+	case "daemonset", "daemonsets", "ds", "deployment", "deployments", "deploy", "statefulset", "statefulsets", "sts":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+)
+
+func TestParseResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		wantType string
+		wantName string
+		wantErr  bool
+	}{
+		{name: "split type and name", resource: "deploy/api", wantType: "deploy", wantName: "api"},
+		{name: "error without name", resource: "deploy/", wantErr: true},
+		{name: "error without type", resource: "api", wantErr: true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			gotType, gotName, err := ParseResource(testCase.resource)
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("ParseResource() error = %v, wantErr %v", err, testCase.wantErr)
+
+				return
+			}
+
+			if gotType != testCase.wantType || gotName != testCase.wantName {
+				t.Errorf("ParseResource() = %v, %v, want %v, %v", gotType, gotName, testCase.wantType, testCase.wantName)
+			}
+		})
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		want         string
+	}{
+		{resourceType: "deploy", want: "deployment/api"},
+		{resourceType: "Deployments", want: "deployment/api"},
+		{resourceType: "deployment", want: "deployment/api"},
+		{resourceType: "cj", want: "cronjob/api"},
+		{resourceType: "ds", want: "daemonset/api"},
+		{resourceType: "jobs", want: "job/api"},
+		{resourceType: "po", want: "pod/api"},
+		{resourceType: "rs", want: "replicaset/api"},
+		{resourceType: "sts", want: "statefulset/api"},
+		{resourceType: "svc", want: "svc/api"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.resourceType, func(t *testing.T) {
+			if got := ResourceName(testCase.resourceType, "api"); got != testCase.want {
+				t.Errorf("ResourceName() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestClient_Workload(t *testing.T) {
+	kubeClient := mock.NewFakeClient(
+		mock.Pod("test", "test", nil, nil, nil),
+		mock.Deployment("test", "test", nil, nil, nil),
+		mock.DaemonSet("test", "test", nil, nil, nil),
+		mock.ReplicaSet("test", "test", nil, nil, nil),
+		mock.StatefulSet("test", "test", nil, nil, nil),
+		mock.Job("test", "test", nil, nil, nil),
+		mock.CronJobv1("test", "test", nil, nil, nil),
+	).WithResources(mock.CronJobv1Resource())
+	beta1Client := mock.NewFakeClient(mock.CronJobv1beta1("test", "test", nil, nil, nil)).
+		WithResources(mock.CronJobv1beta1Resource())
+	unsupportedClient := mock.NewFakeClient(&batchv1.CronJob{}).WithResources(mock.UnsupportedGroupResource())
+	missingGroupClient := mock.NewFakeClient(&batchv1beta1.CronJob{})
+
+	tests := []struct {
+		name         string
+		client       *Client
+		resourceType string
+		wantErr      error
+	}{
+		{name: "pod", client: NewClient(WithKubeClient(kubeClient)), resourceType: "po"},
+		{name: "deployment", client: NewClient(WithKubeClient(kubeClient)), resourceType: "deploy"},
+		{name: "daemonset", client: NewClient(WithKubeClient(kubeClient)), resourceType: "ds"},
+		{name: "replicaset", client: NewClient(WithKubeClient(kubeClient)), resourceType: "rs"},
+		{name: "statefulset", client: NewClient(WithKubeClient(kubeClient)), resourceType: "sts"},
+		{name: "job", client: NewClient(WithKubeClient(kubeClient)), resourceType: "job"},
+		{name: "cronjob v1", client: NewClient(WithKubeClient(kubeClient)), resourceType: "cj"},
+		{name: "cronjob v1beta1", client: NewClient(WithKubeClient(beta1Client)), resourceType: "CronJob"},
+		{
+			name:         "error on unsupported group",
+			client:       NewClient(WithKubeClient(unsupportedClient)),
+			resourceType: "cronjob",
+			wantErr:      ErrUnsupportedGroup,
+		},
+		{
+			name:         "return API group errors",
+			client:       NewClient(WithKubeClient(missingGroupClient)),
+			resourceType: "cronjob",
+			wantErr:      ErrMissingResource,
+		},
+		{
+			name:         "error on unsupported type",
+			client:       NewClient(WithKubeClient(kubeClient)),
+			resourceType: "service",
+			wantErr:      ErrUnsupportedType,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.client.options.Namespace = "test"
+
+			got := testCase.client.Workload(testCase.resourceType, "test")
+			if !errors.Is(got.Error, testCase.wantErr) {
+				t.Errorf("Client.Workload() error = %v, wantErr %v", got.Error, testCase.wantErr)
+			}
+
+			if testCase.wantErr == nil && got.Workload == nil {
+				t.Errorf("Client.Workload().Workload is nil")
+			}
+		})
+	}
+}
+
+func TestClient_Revisions(t *testing.T) {
+	deployment := mock.Deployment("test", "test", nil, nil, nil)
+	daemonSet := mock.DaemonSet("test", "test", nil, nil, nil)
+	statefulSet := mock.StatefulSet("test", "test", nil, nil, nil)
+	kubeClient := mock.NewFakeClient(
+		deployment, daemonSet, statefulSet,
+		mock.DeploymentRevision(deployment, 1, nil),
+		mock.ControllerRevision("DaemonSet", daemonSet, 1, nil),
+		mock.ControllerRevision("StatefulSet", statefulSet, 1, nil),
+	)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		wantErr      error
+	}{
+		{name: "deployment", resourceType: "deploy"},
+		{name: "daemonset", resourceType: "ds"},
+		{name: "statefulset", resourceType: "sts"},
+		{name: "error on unsupported type", resourceType: "pod", wantErr: ErrUnsupportedType},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := NewClient(WithKubeClient(kubeClient), WithNamespace("test")).Revisions(testCase.resourceType, "test")
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Client.Revisions() error = %v, wantErr %v", err, testCase.wantErr)
+			}
+
+			if testCase.wantErr == nil && len(got) != 1 {
+				t.Errorf("Client.Revisions() = %v, want 1 revision", got)
+			}
+		})
+	}
+}
+
+func TestClient_Restart(t *testing.T) {
+	kubeClient := mock.NewFakeClient(
+		mock.Deployment("test", "test", nil, nil, nil),
+		mock.DaemonSet("test", "test", nil, nil, nil),
+		mock.StatefulSet("test", "test", nil, nil, nil),
+	)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		wantErr      error
+	}{
+		{name: "deployment", resourceType: "deploy"},
+		{name: "daemonset", resourceType: "ds"},
+		{name: "statefulset", resourceType: "sts"},
+		{name: "error on unsupported type", resourceType: "pod", wantErr: ErrUnsupportedType},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := NewClient(WithKubeClient(kubeClient), WithNamespace("test")).Restart(testCase.resourceType, "test", time.Now())
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Client.Restart() error = %v, wantErr %v", err, testCase.wantErr)
+			}
+
+			if got := CanRestart(testCase.resourceType); got != (testCase.wantErr == nil) {
+				t.Errorf("CanRestart() = %v, want %v", got, testCase.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+// Package dotenv reads .env files, including the ones written by k8s-dotenv, back into structured data.
+package dotenv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+)
+
+// ErrInvalidLine is returned when a line is neither blank, a comment nor a key/value pair.
+var ErrInvalidLine = errors.New("invalid line")
+
+// ErrUnterminatedValue is returned when a quoted value is not closed before the end of the file.
+var ErrUnterminatedValue = errors.New("unterminated quoted value")
+
+func newLineError(err error, line int, text string) error {
+	return fmt.Errorf("line %d: %w: %q", line, err, text)
+}
+
+// File is a parsed .env file, values are grouped in the sections they appear in.
+type File struct {
+	Sections []*Section
+	// Transforms lists how k8s-dotenv changed the values when it wrote the file, such as redacted or filtered.
+	Transforms []string
+}
+
+// Section holds the values that follow a section header such as `##### SECRET - name #####`,
+// values before the first header belong to an ENVIRONMENT section with an empty name.
+type Section struct {
+	Kind    string
+	Name    string
+	Line    int
+	Entries []Entry
+}
+
+// Entry is a single key/value pair and the line it starts on.
+type Entry struct {
+	Key    string
+	Value  string
+	Export bool
+	Line   int
+}
+
+func headerPattern() *regexp.Regexp {
+	return regexp.MustCompile(`^#{5} (` + result.KindConfigMap + `|` + result.KindSecret + `) - (.+) #{5}$`)
+}
+
+func keyPattern() *regexp.Regexp {
+	return regexp.MustCompile(`^(export\s+)?([^\s=#'"]+)\s*=\s*`)
+}
+
+// Read parses a .env file.
+//
+// Values can be unquoted, single quoted or double quoted, quoted values can span several lines. Double quoted values
+// are unescaped the way `parser.Parse` escapes them: `\\`, `\"`, `\n` and `\r`, other backslashes are kept.
+// Unquoted values end at a ` #` comment.
+func Read(reader io.Reader) (*File, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1<<20) //nolint
+
+	header := headerPattern()
+	key := keyPattern()
+	file := &File{}
+	section := &Section{Kind: result.KindEnvironment}
+	lineNumber := 0
+
+	next := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+
+		lineNumber++
+
+		return strings.TrimSuffix(scanner.Text(), "\r"), true
+	}
+
+	for {
+		line, ok := next()
+		if !ok {
+			break
+		}
+
+		trimmed := strings.TrimSpace(line)
+
+		if match := header.FindStringSubmatch(trimmed); match != nil {
+			if len(section.Entries) > 0 || section.Kind != result.KindEnvironment {
+				file.Sections = append(file.Sections, section)
+			}
+
+			section = &Section{Kind: match[1], Name: match[2], Line: lineNumber}
+
+			continue
+		}
+
+		if transforms := strings.TrimPrefix(trimmed, result.TransformsComment); transforms != trimmed {
+			for _, transform := range strings.Split(transforms, ",") {
+				if transform = strings.TrimSpace(transform); transform != "" {
+					file.Transforms = append(file.Transforms, transform)
+				}
+			}
+
+			continue
+		}
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		match := key.FindStringSubmatch(trimmed)
+		if match == nil {
+			return nil, newLineError(ErrInvalidLine, lineNumber, line)
+		}
+
+		entry := Entry{Key: match[2], Export: match[1] != "", Line: lineNumber}
+		raw := trimmed[len(match[0]):]
+
+		value, err := readValue(raw, next)
+		if err != nil {
+			return nil, newLineError(err, entry.Line, line)
+		}
+
+		entry.Value = value
+		section.Entries = append(section.Entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	if len(section.Entries) > 0 || section.Kind != result.KindEnvironment {
+		file.Sections = append(file.Sections, section)
+	}
+
+	return file, nil
+}
+
+// readValue reads the value starting at raw, reading more lines with next while a quoted value is open.
+// Only whitespace or a comment can follow the closing quote.
+func readValue(raw string, next func() (string, bool)) (string, error) {
+	if raw == "" || (raw[0] != '"' && raw[0] != '\'') {
+		if index := strings.Index(raw, " #"); index != -1 {
+			raw = raw[:index]
+		}
+
+		return strings.TrimSpace(raw), nil
+	}
+
+	quote := raw[0]
+	value := raw[1:]
+
+	for {
+		if end := closingQuote(value, quote); end != -1 {
+			if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return "", ErrInvalidLine
+			}
+
+			value = value[:end]
+
+			break
+		}
+
+		line, ok := next()
+		if !ok {
+			return "", ErrUnterminatedValue
+		}
+
+		value += "\n" + line
+	}
+
+	if quote == '\'' {
+		return value, nil
+	}
+
+	return unescape(value), nil
+}
+
+// closingQuote returns the index of the quote that closes value, quotes escaped with a backslash are skipped in
+// double quoted values.
+func closingQuote(value string, quote byte) int {
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == quote:
+			return i
+		case value[i] == '\\' && quote == '"':
+			i++
+		}
+	}
+
+	return -1
+}
+
+func unescape(value string) string {
+	var res strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			res.WriteByte(value[i])
+
+			continue
+		}
+
+		switch value[i+1] {
+		case 'n':
+			res.WriteByte('\n')
+		case 'r':
+			res.WriteByte('\r')
+		case '"', '\\':
+			res.WriteByte(value[i+1])
+		default:
+			res.WriteByte('\\')
+			res.WriteByte(value[i+1])
+		}
+
+		i++
+	}
+
+	return res.String()
+}
+
+// Result converts the file to a Result, when a key appears more than once in a section the last value wins.
+func (f *File) Result() *result.Result {
+	res := &result.Result{
+		Environment: result.EnvValues{},
+		ConfigMaps:  map[string]result.EnvValues{},
+		Secrets:     map[string]result.EnvValues{},
+	}
+
+	for _, section := range f.Sections {
+		values := res.Environment
+
+		switch section.Kind {
+		case result.KindConfigMap:
+			values = section.values(res.ConfigMaps)
+		case result.KindSecret:
+			values = section.values(res.Secrets)
+		}
+
+		for _, entry := range section.Entries {
+			values[entry.Key] = entry.Value
+		}
+	}
+
+	return res
+}
+
+func (s *Section) values(sources map[string]result.EnvValues) result.EnvValues {
+	if _, found := sources[s.Name]; !found {
+		sources[s.Name] = result.EnvValues{}
+	}
+
+	return sources[s.Name]
+}
+
+// Environment returns every key with the value it would have after sourcing the file, later lines win.
+func (f *File) Environment() map[string]string {
+	res := map[string]string{}
+
+	for _, section := range f.Sections {
+		for _, entry := range section.Entries {
+			res[entry.Key] = entry.Value
+		}
+	}
+
+	return res
+}
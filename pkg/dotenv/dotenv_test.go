@@ -0,0 +1,172 @@
+package dotenv
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/result"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *File
+		wantErr error
+	}{
+		{
+			name: "k8s-dotenv output",
+			content: `export env="a\nb"
+##### CONFIGMAP - cm #####
+export cm="say \"hi\" # not"
+##### SECRET - sec #####
+export sec="val"
+`,
+			want: &File{Sections: []*Section{
+				{Kind: result.KindEnvironment, Entries: []Entry{{Key: "env", Value: "a\nb", Export: true, Line: 1}}},
+				{Kind: result.KindConfigMap, Name: "cm", Line: 2, Entries: []Entry{
+					{Key: "cm", Value: `say "hi" # not`, Export: true, Line: 3},
+				}},
+				{Kind: result.KindSecret, Name: "sec", Line: 4, Entries: []Entry{
+					{Key: "sec", Value: "val", Export: true, Line: 5},
+				}},
+			}},
+		},
+		{
+			name: "dotenv dialects",
+			content: `# comment
+
+PLAIN = value # comment
+EMPTY=
+SINGLE='$HOME\n' # comment
+DOUBLE="quote\"slash\\tab\tother\q" # comment
+MULTI="line 1
+line 2"
+HASH=a#b
+`,
+			want: &File{Sections: []*Section{
+				{Kind: result.KindEnvironment, Entries: []Entry{
+					{Key: "PLAIN", Value: "value", Line: 3},
+					{Key: "EMPTY", Value: "", Line: 4},
+					{Key: "SINGLE", Value: `$HOME\n`, Line: 5},
+					{Key: "DOUBLE", Value: `quote"slash\tab\tother\q`, Line: 6},
+					{Key: "MULTI", Value: "line 1\nline 2", Line: 7},
+					{Key: "HASH", Value: "a#b", Line: 9},
+				}},
+			}},
+		},
+		{
+			name:    "transforms",
+			content: "# k8s-dotenv: redacted, filtered\n##### SECRET - sec #####\nsec=\"********\"\n",
+			want: &File{Transforms: []string{result.TransformRedacted, result.TransformFiltered}, Sections: []*Section{
+				{Kind: result.KindSecret, Name: "sec", Line: 2, Entries: []Entry{{Key: "sec", Value: "********", Line: 3}}},
+			}},
+		},
+		{
+			name:    "empty file",
+			content: "",
+			want:    &File{},
+		},
+		{
+			name:    "error on invalid line",
+			content: "A=1\nnot a pair\n",
+			wantErr: ErrInvalidLine,
+		},
+		{
+			name:    "error on text after a quoted value",
+			content: "A=\"say \"hi\"\n",
+			wantErr: ErrInvalidLine,
+		},
+		{
+			name:    "error on unterminated value",
+			content: "A=\"open\nB=1\n",
+			wantErr: ErrUnterminatedValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(strings.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("Read() = %v", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestFile_Result(t *testing.T) {
+	want := &result.Result{
+		Environment: result.EnvValues{"env": "val\n", "k": "v"},
+		ConfigMaps:  map[string]result.EnvValues{"cm": {"cm": "val"}},
+		Secrets:     map[string]result.EnvValues{"sec": {"sec": "val"}, "empty": {}},
+	}
+
+	var buf bytes.Buffer
+	if err := want.Write(&buf); err != nil {
+		t.Fatalf("Result.Write() error = %v", err)
+	}
+
+	file, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if got := file.Result(); !cmp.Equal(got, want, cmp.AllowUnexported(result.Result{})) {
+		t.Errorf("File.Result() = %v", cmp.Diff(want, got, cmp.AllowUnexported(result.Result{})))
+	}
+}
+
+func TestRead_roundTrip(t *testing.T) {
+	values := []string{
+		`^\d+\\$`,
+		`{"json":"a\nb"}`,
+		`say "hi" # not`,
+		"multi\nline\r\n",
+		`C:\`,
+		`'single'`,
+		"",
+	}
+
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			want := &result.Result{
+				Environment: result.EnvValues{"key": value},
+				ConfigMaps:  map[string]result.EnvValues{},
+				Secrets:     map[string]result.EnvValues{},
+			}
+
+			var buf bytes.Buffer
+			if err := want.Write(&buf); err != nil {
+				t.Fatalf("Result.Write() error = %v", err)
+			}
+
+			file, err := Read(&buf)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+
+			if got := file.Environment()["key"]; got != value {
+				t.Errorf("Read() = %q, want %q", got, value)
+			}
+		})
+	}
+}
+
+func TestFile_Environment(t *testing.T) {
+	file, err := Read(strings.NewReader("a=1\n##### SECRET - sec #####\nb=2\n##### CONFIGMAP - cm #####\na=3\n"))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	want := map[string]string{"a": "3", "b": "2"}
+	if got := file.Environment(); !cmp.Equal(got, want) {
+		t.Errorf("File.Environment() = %v", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,218 @@
+// Package encryption encrypts output with age, for age X25519 and SSH recipients, and decrypts it with local identities.
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"filippo.io/age/armor"
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrNoRecipients is returned when encrypting without any recipient.
+var ErrNoRecipients = errors.New("no recipients")
+
+// ErrNoIdentities is returned when decrypting without any identity.
+var ErrNoIdentities = errors.New("no identities")
+
+const armorHeader = "-----BEGIN AGE ENCRYPTED FILE-----"
+
+func newEncryptionError(err error) error {
+	return fmt.Errorf("encryption error: %w", err)
+}
+
+func newDecryptionError(err error) error {
+	return fmt.Errorf("decryption error: %w", err)
+}
+
+// parseRecipient parses an age X25519 public key or an SSH public key.
+func parseRecipient(value string) (age.Recipient, error) {
+	if strings.HasPrefix(value, "ssh-") {
+		//nolint
+		return agessh.ParseRecipient(value)
+	}
+
+	//nolint
+	return age.ParseX25519Recipient(value)
+}
+
+// ParseRecipients parses recipients given as age X25519 public keys (`age1...`), SSH public keys or paths to files
+// holding one of those per line, such as `~/.ssh/id_ed25519.pub`.
+func ParseRecipients(values []string) ([]age.Recipient, error) {
+	recipients := []age.Recipient{}
+
+	for _, value := range values {
+		lines := []string{value}
+
+		if !strings.HasPrefix(value, "age1") && !strings.HasPrefix(value, "ssh-") {
+			content, err := os.ReadFile(value)
+			if err != nil {
+				return nil, fmt.Errorf("reading recipients: %w", err)
+			}
+
+			lines = strings.Split(string(content), "\n")
+		}
+
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			recipient, err := parseRecipient(line)
+			if err != nil {
+				return nil, fmt.Errorf("parsing recipient %q: %w", value, err)
+			}
+
+			recipients = append(recipients, recipient)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	return recipients, nil
+}
+
+// Encrypt encrypts data to every recipient, the result is ASCII armored so it can be printed and copied.
+func Encrypt(data []byte, recipients []age.Recipient) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	var buf bytes.Buffer
+
+	armored := armor.NewWriter(&buf)
+
+	writer, err := age.Encrypt(armored, recipients...)
+	if err != nil {
+		return nil, newEncryptionError(err)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, newEncryptionError(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, newEncryptionError(err)
+	}
+
+	if err := armored.Close(); err != nil {
+		return nil, newEncryptionError(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// IsEncrypted reports whether data is an age encrypted file, armored or not.
+func IsEncrypted(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+
+	return bytes.HasPrefix(trimmed, []byte(armorHeader)) || bytes.HasPrefix(trimmed, []byte("age-encryption.org/"))
+}
+
+// DefaultIdentities returns the identity files used when none is given: `$K8S_DOTENV_IDENTITY`,
+// or the default SSH keys that exist.
+func DefaultIdentities() []string {
+	if identity := os.Getenv("K8S_DOTENV_IDENTITY"); identity != "" {
+		return []string{identity}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	res := []string{}
+
+	for _, name := range []string{"id_ed25519", "id_rsa"} {
+		if _, err := os.Stat(filepath.Join(home, ".ssh", name)); err == nil {
+			res = append(res, filepath.Join(home, ".ssh", name))
+		}
+	}
+
+	return res
+}
+
+// ParseIdentities reads age identity files (`AGE-SECRET-KEY-1...`) and SSH private keys, passphrase is called
+// when an SSH key is encrypted.
+func ParseIdentities(files []string, passphrase func(name string) ([]byte, error)) ([]age.Identity, error) {
+	identities := []age.Identity{}
+
+	for _, name := range files {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("reading identity: %w", err)
+		}
+
+		parsed, err := parseIdentity(name, content, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("parsing identity %s: %w", name, err)
+		}
+
+		identities = append(identities, parsed...)
+	}
+
+	if len(identities) == 0 {
+		return nil, ErrNoIdentities
+	}
+
+	return identities, nil
+}
+
+func parseIdentity(name string, content []byte, passphrase func(name string) ([]byte, error)) ([]age.Identity, error) {
+	if !bytes.Contains(content, []byte("PRIVATE KEY")) {
+		//nolint
+		return age.ParseIdentities(bufio.NewReader(bytes.NewReader(content)))
+	}
+
+	identity, err := agessh.ParseIdentity(content)
+	if err == nil {
+		return []age.Identity{identity}, nil
+	}
+
+	var missing *ssh.PassphraseMissingError
+	if !errors.As(err, &missing) || missing.PublicKey == nil || passphrase == nil {
+		//nolint
+		return nil, err
+	}
+
+	encrypted, err := agessh.NewEncryptedSSHIdentity(missing.PublicKey, content, func() ([]byte, error) {
+		return passphrase(name)
+	})
+	if err != nil {
+		//nolint
+		return nil, err
+	}
+
+	return []age.Identity{encrypted}, nil
+}
+
+// Decrypt decrypts an age encrypted file, armored or not, with the first identity that matches.
+func Decrypt(data []byte, identities []age.Identity) ([]byte, error) {
+	var reader io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(armorHeader)) {
+		reader = armor.NewReader(bytes.NewReader(bytes.TrimSpace(data)))
+	}
+
+	decrypted, err := age.Decrypt(reader, identities...)
+	if err != nil {
+		return nil, newDecryptionError(err)
+	}
+
+	res, err := io.ReadAll(decrypted)
+	if err != nil {
+		return nil, newDecryptionError(err)
+	}
+
+	return res, nil
+}
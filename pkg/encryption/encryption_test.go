@@ -0,0 +1,160 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"golang.org/x/crypto/ssh"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	name = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestParseRecipients(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipient := identity.Recipient().String()
+	file := writeFile(t, "recipients.txt", []byte("# team\n"+recipient+"\n\n"+recipient+"\n"))
+	empty := writeFile(t, "empty.txt", []byte("# nobody\n"))
+
+	tests := []struct {
+		name      string
+		values    []string
+		want      int
+		wantErr   bool
+		wantErrIs error
+	}{
+		{name: "age key", values: []string{recipient}, want: 1},
+		{name: "recipients file", values: []string{file}, want: 2},
+		{name: "no recipients", values: []string{empty}, wantErr: true, wantErrIs: ErrNoRecipients},
+		{name: "missing file", values: []string{filepath.Join(t.TempDir(), "missing")}, wantErr: true, wantErrIs: os.ErrNotExist},
+		{name: "invalid key", values: []string{"age1invalid"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRecipients(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRecipients() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("ParseRecipients() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if len(got) != tt.want {
+				t.Errorf("ParseRecipients() = %d recipients, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestEncrypt_roundTrip(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := Encrypt([]byte("secret=value\n"), []age.Recipient{identity.Recipient()})
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	if !IsEncrypted(encrypted) {
+		t.Errorf("IsEncrypted() = false, want true")
+	}
+
+	identities, err := ParseIdentities([]string{writeFile(t, "keys.txt", []byte(identity.String()+"\n"))}, nil)
+	if err != nil {
+		t.Fatalf("ParseIdentities() error = %v", err)
+	}
+
+	got, err := Decrypt(encrypted, identities)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+
+	if string(got) != "secret=value\n" {
+		t.Errorf("Decrypt() = %q, want %q", got, "secret=value\n")
+	}
+
+	other, _ := age.GenerateX25519Identity()
+	if _, err := Decrypt(encrypted, []age.Identity{other}); err == nil {
+		t.Errorf("Decrypt() with another identity error = nil, want error")
+	}
+}
+
+func TestEncrypt_ssh(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	public, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipients, err := ParseRecipients([]string{writeFile(t, "id_rsa.pub", ssh.MarshalAuthorizedKey(public))})
+	if err != nil {
+		t.Fatalf("ParseRecipients() error = %v", err)
+	}
+
+	encrypted, err := Encrypt([]byte("k=v"), recipients)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	identities, err := ParseIdentities([]string{writeFile(t, "id_rsa", private)}, nil)
+	if err != nil {
+		t.Fatalf("ParseIdentities() error = %v", err)
+	}
+
+	if got, err := Decrypt(encrypted, identities); err != nil || string(got) != "k=v" {
+		t.Errorf("Decrypt() = %q, %v, want %q", got, err, "k=v")
+	}
+}
+
+func TestEncrypt_noRecipients(t *testing.T) {
+	if _, err := Encrypt([]byte("k=v"), nil); !errors.Is(err, ErrNoRecipients) {
+		t.Errorf("Encrypt() error = %v, wantErr %v", err, ErrNoRecipients)
+	}
+}
+
+func TestParseIdentities(t *testing.T) {
+	if _, err := ParseIdentities(nil, nil); !errors.Is(err, ErrNoIdentities) {
+		t.Errorf("ParseIdentities() error = %v, wantErr %v", err, ErrNoIdentities)
+	}
+
+	if _, err := ParseIdentities([]string{filepath.Join(t.TempDir(), "missing")}, nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ParseIdentities() error = %v, wantErr %v", err, os.ErrNotExist)
+	}
+}
+
+func TestDefaultIdentities(t *testing.T) {
+	t.Setenv("K8S_DOTENV_IDENTITY", "/keys.txt")
+
+	if got := DefaultIdentities(); len(got) != 1 || got[0] != "/keys.txt" {
+		t.Errorf("DefaultIdentities() = %v, want [/keys.txt]", got)
+	}
+}
@@ -0,0 +1,126 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// systemConfig is the system wide git config file, skipped when `$GIT_CONFIG_NOSYSTEM` is set like git does.
+const systemConfig = "/etc/gitconfig"
+
+// configFiles returns the git config files in the order git reads them, later files override earlier ones: the
+// system config, the global configs in `$XDG_CONFIG_HOME/git/config` and `~/.gitconfig`, then the repository config.
+func configFiles(gitDir string) []string {
+	files := []string{}
+	if os.Getenv("GIT_CONFIG_NOSYSTEM") == "" {
+		files = append(files, systemConfig)
+	}
+
+	if xdg := xdgConfigHome(); xdg != "" {
+		files = append(files, filepath.Join(xdg, "git", "config"))
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".gitconfig"))
+	}
+
+	return append(files, filepath.Join(commonDir(gitDir), "config"))
+}
+
+// xdgConfigHome returns `$XDG_CONFIG_HOME`, defaulting to `~/.config`.
+func xdgConfigHome() string {
+	if config := os.Getenv("XDG_CONFIG_HOME"); config != "" {
+		return config
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config")
+}
+
+// configValue returns the last value of name in section across files, section and name are lower case since git
+// compares them case insensitively. Subsections and `include` directives are not supported.
+func configValue(files []string, section, name string) string {
+	res := ""
+
+	for _, file := range files {
+		if value, found := readConfigValue(file, section, name); found {
+			res = value
+		}
+	}
+
+	return res
+}
+
+// readConfigValue returns the last value of name in section of a git config file, missing files have no values.
+func readConfigValue(file, section, name string) (string, bool) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", false
+	}
+
+	var res, current string
+
+	found := false
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[':
+			header, rest, _ := strings.Cut(line[1:], "]")
+			current = strings.ToLower(strings.TrimSpace(header))
+			line = strings.TrimSpace(rest)
+
+			if line == "" {
+				continue
+			}
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		if current == section && strings.ToLower(strings.TrimSpace(key)) == name {
+			res, found = configString(value), true
+		}
+	}
+
+	return res, found
+}
+
+// configString returns a config value without its quotes or trailing comment.
+func configString(value string) string {
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(value, `"`) {
+		if end := strings.Index(value[1:], `"`); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+
+	if i := strings.IndexAny(value, "#;"); i >= 0 {
+		value = value[:i]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// expandHome expands a leading `~/` to the home directory, as git does for paths in its config.
+func expandHome(name string) string {
+	if !strings.HasPrefix(name, "~/") {
+		return name
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return name
+	}
+
+	return filepath.Join(home, name[2:])
+}
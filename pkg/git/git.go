@@ -0,0 +1,153 @@
+// Package git tells whether a file is tracked or ignored by git by reading the repository directly,
+// without running git.
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Status describes a path relative to the git repository it is in.
+type Status struct {
+	// Repository is the root of the working tree, it is empty when the path is not in a repository.
+	Repository string
+	// Tracked is set when the path is in the index.
+	Tracked bool
+	// Ignored is set when the path matches a .gitignore, .git/info/exclude or global excludes rule.
+	Ignored bool
+}
+
+// Exposed reports whether the path could end up in a commit: it is tracked or it is not ignored.
+func (s Status) Exposed() bool {
+	return s.Repository != "" && (s.Tracked || !s.Ignored)
+}
+
+// findRepository walks up from dir to the first directory holding `.git`, it returns the working tree root and
+// the git directory. A `.git` file, as used by worktrees and submodules, points to the git directory.
+func findRepository(dir string) (string, string, error) {
+	for {
+		dotGit := filepath.Join(dir, ".git")
+
+		info, err := os.Stat(dotGit)
+		if err == nil && info.IsDir() {
+			return dir, dotGit, nil
+		}
+
+		if err == nil {
+			content, err := os.ReadFile(dotGit)
+			if err != nil {
+				return "", "", fmt.Errorf("reading .git file: %w", err)
+			}
+
+			gitDir := strings.TrimSpace(strings.TrimPrefix(string(content), "gitdir:"))
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(dir, gitDir)
+			}
+
+			return dir, gitDir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+
+		dir = parent
+	}
+}
+
+// commonDir returns the directory shared by every worktree of a repository, where `info/exclude` lives.
+func commonDir(gitDir string) string {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+
+	common := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(gitDir, common)
+	}
+
+	return common
+}
+
+// globalExcludes returns the global excludes file, `core.excludesFile` from the git config or its default location.
+func globalExcludes(gitDir string) string {
+	if name := configValue(configFiles(gitDir), "core", "excludesfile"); name != "" {
+		return expandHome(name)
+	}
+
+	if config := xdgConfigHome(); config != "" {
+		return filepath.Join(config, "git", "ignore")
+	}
+
+	return ""
+}
+
+// nearestDir returns the closest existing directory holding name, which may not exist yet.
+func nearestDir(name string) string {
+	dir := filepath.Dir(name)
+
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+
+		dir = parent
+	}
+}
+
+// PathStatus returns whether name, which does not need to exist, is tracked or ignored by git.
+func PathStatus(name string) (Status, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return Status{}, fmt.Errorf("resolving path: %w", err)
+	}
+
+	dir := nearestDir(abs)
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		abs = filepath.Join(resolved, strings.TrimPrefix(abs, dir))
+		dir = resolved
+	}
+
+	root, gitDir, err := findRepository(dir)
+	if err != nil || root == "" {
+		return Status{}, err
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return Status{}, fmt.Errorf("resolving path: %w", err)
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	index, err := readIndex(filepath.Join(gitDir, "index"))
+	if err != nil {
+		return Status{}, err
+	}
+
+	patterns := []*ignorePattern{}
+	if global := globalExcludes(gitDir); global != "" {
+		patterns = append(patterns, readIgnoreFile(global, "")...)
+	}
+
+	patterns = append(patterns, readIgnoreFile(filepath.Join(commonDir(gitDir), "info", "exclude"), "")...)
+
+	base := ""
+	parts := strings.Split(rel, "/")
+
+	for i := 0; i < len(parts); i++ {
+		patterns = append(patterns, readIgnoreFile(filepath.Join(root, filepath.FromSlash(base), ".gitignore"), base)...)
+		base = strings.TrimPrefix(base+"/"+parts[i], "/")
+	}
+
+	return Status{Repository: root, Tracked: index[rel], Ignored: ignored(patterns, rel)}, nil
+}
@@ -0,0 +1,202 @@
+package git
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// index builds an index file holding paths, with the given version.
+func index(version uint32, paths ...string) []byte {
+	data := []byte(indexSignature)
+	data = binary.BigEndian.AppendUint32(data, version)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(paths)))
+	previous := ""
+
+	for _, path := range paths {
+		entry := make([]byte, indexEntrySize)
+		binary.BigEndian.PutUint16(entry[indexEntrySize-2:], uint16(len(path)))
+
+		if version == 4 {
+			entry = append(entry, byte(len(previous)))
+			entry = append(entry, path...)
+			entry = append(entry, 0)
+		} else {
+			entry = append(entry, path...)
+			entry = append(entry, make([]byte, indexEntryAlign-len(entry)%indexEntryAlign)...)
+		}
+
+		data = append(data, entry...)
+		previous = path
+	}
+
+	return data
+}
+
+func writeFile(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_readIndex(t *testing.T) {
+	for _, version := range []uint32{2, 3, 4} {
+		name := filepath.Join(t.TempDir(), "index")
+		writeFile(t, name, index(version, ".env", "app/.env.local", "app/main.go"))
+
+		got, err := readIndex(name)
+		if err != nil {
+			t.Fatalf("readIndex() v%d error = %v", version, err)
+		}
+
+		if len(got) != 3 || !got[".env"] || !got["app/.env.local"] || !got["app/main.go"] {
+			t.Errorf("readIndex() v%d = %v", version, got)
+		}
+	}
+
+	t.Run("missing index", func(t *testing.T) {
+		got, err := readIndex(filepath.Join(t.TempDir(), "index"))
+		if err != nil || len(got) != 0 {
+			t.Errorf("readIndex() = %v, %v, want empty", got, err)
+		}
+	})
+
+	t.Run("error on invalid index", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "index")
+		writeFile(t, name, []byte("DIRX"))
+
+		if _, err := readIndex(name); err == nil {
+			t.Errorf("readIndex() error = nil, want error")
+		}
+	})
+}
+
+func Test_ignored(t *testing.T) {
+	patterns := []*ignorePattern{
+		parseIgnorePattern("", "# comment"),
+		parseIgnorePattern("", "*.env"),
+		parseIgnorePattern("", "!keep.env"),
+		parseIgnorePattern("", "/secrets/"),
+		parseIgnorePattern("", "build/**/out"),
+		parseIgnorePattern("", "tmp[0-9]"),
+		parseIgnorePattern("app", ".env.*"),
+		parseIgnorePattern("app", `\#literal`),
+		parseIgnorePattern("", "[z-a]"),
+		parseIgnorePattern("", "[]"),
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "match basename at any depth", path: "a/b/prod.env", want: true},
+		{name: "negated pattern", path: "keep.env", want: false},
+		{name: "directory only pattern", path: "secrets/.env.local", want: true},
+		{name: "anchored directory", path: "a/secrets/file", want: false},
+		{name: "double star", path: "build/x/y/out", want: true},
+		{name: "character class", path: "tmp1", want: true},
+		{name: "pattern from nested gitignore", path: "app/.env.local", want: true},
+		{name: "nested pattern does not apply outside its directory", path: ".env.local", want: false},
+		{name: "escaped hash", path: "app/#literal", want: true},
+		{name: "not ignored", path: "main.go", want: false},
+		{name: "invalid patterns match nothing", path: "z", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid := []*ignorePattern{}
+
+			for _, pattern := range patterns {
+				if pattern != nil {
+					valid = append(valid, pattern)
+				}
+			}
+
+			if got := ignored(valid, tt.path); got != tt.want {
+				t.Errorf("ignored(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_configValue(t *testing.T) {
+	dir := t.TempDir()
+	global := filepath.Join(dir, "global")
+	local := filepath.Join(dir, "local")
+
+	writeFile(t, global, []byte("[user]\n\tname = dev\n[Core]\n\tExcludesFile = \"~/global ignore\" # comment\n"))
+	writeFile(t, local, []byte("; comment\n[remote \"core\"]\n\texcludesfile = remote\n[core] excludesfile = local ; comment\n"))
+
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{name: "quoted value with mixed case names", files: []string{global}, want: "~/global ignore"},
+		{name: "later files override", files: []string{global, local}, want: "local"},
+		{name: "missing files", files: []string{filepath.Join(dir, "missing")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := configValue(tt.files, "core", "excludesfile"); got != tt.want {
+				t.Errorf("configValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathStatus(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	writeFile(t, filepath.Join(home, ".gitconfig"), []byte("[core]\n\texcludesFile = ~/.gitignore_global\n"))
+	writeFile(t, filepath.Join(home, ".gitignore_global"), []byte("global\n"))
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".git", "index"), index(2, "tracked.env"))
+	writeFile(t, filepath.Join(root, ".gitignore"), []byte("*.env\n"))
+	writeFile(t, filepath.Join(root, ".git", "info", "exclude"), []byte("excluded\n"))
+	writeFile(t, filepath.Join(root, "worktree", ".git"), []byte("gitdir: ../.git\n"))
+
+	outside := t.TempDir()
+
+	tests := []struct {
+		name        string
+		path        string
+		wantExposed bool
+		wantIgnored bool
+		wantTracked bool
+	}{
+		{name: "ignored", path: filepath.Join(root, "new.env"), wantIgnored: true},
+		{name: "tracked and ignored", path: filepath.Join(root, "tracked.env"), wantExposed: true, wantIgnored: true, wantTracked: true},
+		{name: "not ignored", path: filepath.Join(root, "sub", "dir", "config"), wantExposed: true},
+		{name: "excluded", path: filepath.Join(root, "excluded"), wantIgnored: true},
+		{name: "core.excludesFile", path: filepath.Join(root, "global"), wantIgnored: true},
+		{name: "git file", path: filepath.Join(root, "worktree", "config"), wantExposed: true},
+		{name: "outside a repository", path: filepath.Join(outside, ".env")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PathStatus(tt.path)
+			if err != nil {
+				t.Fatalf("PathStatus() error = %v", err)
+			}
+
+			if got.Exposed() != tt.wantExposed || got.Ignored != tt.wantIgnored || got.Tracked != tt.wantTracked {
+				t.Errorf("PathStatus() = %+v, want exposed %v, ignored %v, tracked %v",
+					got, tt.wantExposed, tt.wantIgnored, tt.wantTracked)
+			}
+		})
+	}
+}
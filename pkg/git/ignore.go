@@ -0,0 +1,168 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ignorePattern is a single .gitignore rule, base is the directory of the file it was read from.
+type ignorePattern struct {
+	base    string
+	regexp  *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// parseIgnorePattern converts a .gitignore line to a pattern, it returns nil for blank lines, comments and patterns
+// that cannot be parsed such as `[z-a]`, which match nothing.
+func parseIgnorePattern(base, line string) *ignorePattern {
+	line = strings.TrimSuffix(line, "\r")
+
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = strings.TrimSuffix(line, " ")
+	}
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	pattern := &ignorePattern{base: base}
+
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	if line == "" {
+		return nil
+	}
+
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := "^(?:.*/)?" + globRegexp(line) + "$"
+	if anchored {
+		expr = "^" + globRegexp(line) + "$"
+	}
+
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return nil
+	}
+
+	pattern.regexp = compiled
+
+	return pattern
+}
+
+// globRegexp converts a gitignore glob to a regular expression.
+func globRegexp(glob string) string {
+	var res strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		switch char := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			res.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**") && i+2 == len(glob) && (i == 0 || glob[i-1] == '/'):
+			res.WriteString(".*")
+			i++
+		case char == '*':
+			res.WriteString("[^/]*")
+		case char == '?':
+			res.WriteString("[^/]")
+		case char == '\\' && i+1 < len(glob):
+			i++
+			res.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case char == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end == -1 {
+				res.WriteString(`\[`)
+
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			res.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			res.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+
+	return res.String()
+}
+
+// match reports whether the pattern applies to name, a slash separated path relative to the repository root.
+func (p *ignorePattern) match(name string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+
+	if p.base != "" {
+		if !strings.HasPrefix(name, p.base+"/") {
+			return false
+		}
+
+		name = name[len(p.base)+1:]
+	}
+
+	return p.regexp.MatchString(name)
+}
+
+// readIgnoreFile reads the patterns of a .gitignore style file, missing files have no patterns.
+func readIgnoreFile(name, base string) []*ignorePattern {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil
+	}
+
+	defer file.Close()
+
+	patterns := []*ignorePattern{}
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		if pattern := parseIgnorePattern(base, scanner.Text()); pattern != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// ignored reports whether name is ignored, a path is ignored when it or any of its parent directories matches.
+// Patterns are in increasing order of precedence and the last match wins.
+func ignored(patterns []*ignorePattern, name string) bool {
+	parts := strings.Split(name, "/")
+
+	for i := range parts {
+		current := path.Join(parts[:i+1]...)
+		isDir := i < len(parts)-1
+		result := false
+
+		for _, pattern := range patterns {
+			if pattern.match(current, isDir) {
+				result = !pattern.negate
+			}
+		}
+
+		if result {
+			return true
+		}
+	}
+
+	return false
+}
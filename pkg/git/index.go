@@ -0,0 +1,113 @@
+package git
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrInvalidIndex is returned when the index file cannot be read.
+var ErrInvalidIndex = errors.New("invalid git index")
+
+const (
+	indexSignature    = "DIRC"
+	indexHeaderSize   = 12
+	indexEntrySize    = 62
+	indexExtendedFlag = 0x4000
+	indexNameMask     = 0xFFF
+	indexEntryAlign   = 8
+)
+
+func newIndexError(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidIndex, reason)
+}
+
+// readIndex returns the paths of every file in the index, versions 2 to 4 are supported.
+func readIndex(name string) (map[string]bool, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]bool{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading git index: %w", err)
+	}
+
+	if len(data) < indexHeaderSize || string(data[:4]) != indexSignature {
+		return nil, newIndexError("bad signature")
+	}
+
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil, newIndexError(fmt.Sprintf("unsupported version %d", version))
+	}
+
+	count := binary.BigEndian.Uint32(data[8:12])
+	paths := make(map[string]bool, count)
+	pos := indexHeaderSize
+	previous := ""
+
+	for i := uint32(0); i < count; i++ {
+		if pos+indexEntrySize > len(data) {
+			return nil, newIndexError("truncated entry")
+		}
+
+		flags := binary.BigEndian.Uint16(data[pos+indexEntrySize-2 : pos+indexEntrySize])
+		nameStart := pos + indexEntrySize
+
+		if version >= 3 && flags&indexExtendedFlag != 0 {
+			nameStart += 2
+		}
+
+		var path string
+
+		if version == 4 { //nolint
+			strip, size := indexVarint(data[nameStart:])
+			if size == 0 || strip > uint64(len(previous)) {
+				return nil, newIndexError("bad path prefix")
+			}
+
+			end := bytes.IndexByte(data[nameStart+size:], 0)
+			if end == -1 {
+				return nil, newIndexError("truncated path")
+			}
+
+			path = previous[:len(previous)-int(strip)] + string(data[nameStart+size:nameStart+size+end])
+			pos = nameStart + size + end + 1
+		} else {
+			end := bytes.IndexByte(data[nameStart:], 0)
+			if end == -1 || (int(flags&indexNameMask) != indexNameMask && end != int(flags&indexNameMask)) {
+				return nil, newIndexError("truncated path")
+			}
+
+			path = string(data[nameStart : nameStart+end])
+			pos += (nameStart - pos + end + indexEntryAlign) &^ (indexEntryAlign - 1)
+		}
+
+		paths[path] = true
+		previous = path
+	}
+
+	return paths, nil
+}
+
+// indexVarint decodes the offset encoding used by index version 4, it returns the value and the bytes read.
+func indexVarint(data []byte) (uint64, int) {
+	var value uint64
+
+	for i, char := range data {
+		if i == 0 {
+			value = uint64(char & 0x7F) //nolint
+		} else {
+			value = ((value + 1) << 7) | uint64(char&0x7F) //nolint
+		}
+
+		if char&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+
+	return 0, 0
+}
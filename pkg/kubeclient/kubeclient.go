@@ -2,33 +2,72 @@ package kubeclient
 
 import (
 	"errors"
-	"path/filepath"
+	"fmt"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-// ErrMissingHomeDir is returned when the home directory for the current user cannot be found.
-var ErrMissingHomeDir = errors.New("unable to find home directory")
+// ErrReadingKubeConfig is returned when the kubeconfig, `$KUBECONFIG` or `~/.kube/config`, cannot be read.
+var ErrReadingKubeConfig = errors.New("unable to read kubeconfig")
 
-// ErrReadingKubeConfig is returned when ~/.kube/config cannot be read.
-var ErrReadingKubeConfig = errors.New("unable to read ~/.kube/config")
-
-// ErrCreatingKubeClient is returned when ~/.kube/config cannot be parsed.
-var ErrCreatingKubeClient = errors.New("unable to parse ~/.kube/config")
+// ErrCreatingKubeClient is returned when the kubeconfig cannot be parsed.
+var ErrCreatingKubeClient = errors.New("unable to parse kubeconfig")
 
 // ErrNamespaceResolution is returned when the current namespace cannot be resolved.
 var ErrNamespaceResolution = errors.New("current namespace could not be resolved")
 
-// GetDefault returns a kubernetes clientset by reading the current users ~/.kube/config.
+// ErrMissingContext is returned when a context cannot be found in the kubeconfig.
+var ErrMissingContext = errors.New("context not found in kubeconfig")
+
+// GetDefault returns a kubernetes clientset for the current context of the kubeconfig, read from the files listed in
+// `$KUBECONFIG` or from `~/.kube/config` like kubectl does.
 func GetDefault() (kubernetes.Interface, error) {
-	var home string
-	if home = homedir.HomeDir(); home == "" {
-		return nil, ErrMissingHomeDir
+	return ForContext("")
+}
+
+// CurrentNamespace returns the namespace of the current context of the kubeconfig.
+func CurrentNamespace() (string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+
+	clientCfg, err := rules.Load()
+	if err != nil {
+		return "", ErrNamespaceResolution
 	}
 
-	config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(home, ".kube", "config"))
+	if clientCfg.Contexts[clientCfg.CurrentContext].Namespace == "" {
+		return "default", nil
+	}
+
+	return clientCfg.Contexts[clientCfg.CurrentContext].Namespace, nil
+}
+
+// contextConfig returns the kubeconfig with context as the current context, the kubeconfig current context when empty.
+func contextConfig(context string) (clientcmd.ClientConfig, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+
+	if context != "" {
+		raw, err := rules.Load()
+		if err != nil {
+			return nil, ErrReadingKubeConfig
+		}
+
+		if _, found := raw.Contexts[context]; !found {
+			return nil, fmt.Errorf("%w: %s", ErrMissingContext, context)
+		}
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: context}), nil
+}
+
+// ForContext returns a kubernetes clientset for a context of the kubeconfig, the current context when empty.
+func ForContext(context string) (kubernetes.Interface, error) {
+	clientConfig, err := contextConfig(context)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := clientConfig.ClientConfig()
 	if err != nil {
 		return nil, ErrReadingKubeConfig
 	}
@@ -41,18 +80,21 @@ func GetDefault() (kubernetes.Interface, error) {
 	return clientset, nil
 }
 
-// CurrentNamespace returns the namespace from `~/.kube/config`.
-func CurrentNamespace() (string, error) {
-	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+// ContextNamespace returns the namespace of a context of the kubeconfig, the current context when empty.
+func ContextNamespace(context string) (string, error) {
+	if context == "" {
+		return CurrentNamespace()
+	}
 
-	clientCfg, err := rules.Load()
+	clientConfig, err := contextConfig(context)
 	if err != nil {
-		return "", ErrNamespaceResolution
+		return "", err
 	}
 
-	if clientCfg.Contexts[clientCfg.CurrentContext].Namespace == "" {
-		return "default", nil
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return "", ErrNamespaceResolution
 	}
 
-	return clientCfg.Contexts[clientCfg.CurrentContext].Namespace, nil
+	return namespace, nil
 }
@@ -8,19 +8,34 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// DefaultConsole is a command annotation, commands with it write to the console unless an output file is given.
+const DefaultConsole = "k8s-dotenv/default-console"
+
+// NoOutput is a command annotation, commands with it never write an output file and write to the console.
+const NoOutput = "k8s-dotenv/no-output"
+
 // CLI stores configuration and arguments passed to the cli.
 type CLI struct {
-	KubeClient   kubernetes.Interface
-	Namespace    string
-	ResourceName string
-	Filename     string
-	NoExport     bool
-	Writer       io.Writer
+	KubeClient     kubernetes.Interface
+	Context        string
+	Namespace      string
+	ResourceName   string
+	Filename       string
+	Mode           string
+	Backup         bool
+	AllowUnignored bool
+	EncryptTo      []string
+	NoExport       bool
+	Container      string
+	Revision       int64
+	Watch          bool
+	Output         Output
+	Writer         io.Writer
 }
 
 // ResolveNamespace sets the Namespace property of an Options struct.
 func (cli *CLI) ResolveNamespace() error {
-	ns, err := kubeclient.CurrentNamespace()
+	ns, err := kubeclient.ContextNamespace(cli.Context)
 	if err != nil {
 		return fmt.Errorf("resolve namespace: %w", err)
 	}
@@ -4,4 +4,7 @@ package options
 type Client struct {
 	Namespace    string
 	ShouldExport bool
+	Container    string
+	Revision     int64
+	Output       Output
 }
@@ -0,0 +1,73 @@
+package options
+
+import (
+	"io"
+	"os"
+)
+
+// Output stores configuration used when rendering a result.
+type Output struct {
+	// Format is the name of the output format, an empty value renders a .env file.
+	Format string
+	// SystemdUnit renders a systemd drop-in for the named unit instead of an `EnvironmentFile`.
+	SystemdUnit string
+	// ConfigMapName is the name of the generated ConfigMap, defaults to the workload name.
+	ConfigMapName string
+	// SecretName is the name of the generated Secret, defaults to the workload name.
+	SecretName string
+	// ManifestNamespace is the namespace set on generated manifests, it is omitted when empty.
+	ManifestNamespace string
+	// PatchWorkload also renders the workload, changed to load its environment from the generated manifests.
+	PatchWorkload bool
+	// SpringKeys converts keys to Spring property names in the properties, toml and ini formats.
+	SpringKeys bool
+	// KeySeparators are used to split keys into nested objects in the nested-json format, defaults to `__`.
+	KeySeparators []string
+	// TFVarsVariable renders a single map variable with this name in the tfvars format instead of a variable per key.
+	TFVarsVariable string
+	// Template is the text/template used by the template format.
+	Template string
+	// RunConfiguration is the name of the IDE run configuration created or updated by the vscode and jetbrains
+	// formats, defaults to the workload name.
+	RunConfiguration string
+	// Original is the content of the output file before it is written, formats that update a file in place
+	// such as vscode and jetbrains render it with their changes applied.
+	Original []byte
+	// Expand lists the keys whose JSON or YAML object or array values are expanded into a key per nested value,
+	// written like Include.
+	Expand []string
+	// ExpandSeparator joins the parts of expanded keys, defaults to `_`.
+	ExpandSeparator string
+	// ExpandDepth is the number of levels expanded, deeper values are kept as JSON. 0 expands every level.
+	ExpandDepth int
+	// Include keeps only the keys matching one of these globs, or regular expressions between slashes. A pattern
+	// prefixed with `env:`, `configmap:` or `secret:` only applies to that kind of source.
+	Include []string
+	// Exclude drops the keys matching one of these patterns, written like Include.
+	Exclude []string
+	// Rename renames keys, each written as `OLD=NEW`.
+	Rename []string
+	// Set overrides keys with literal values, each written as `KEY=VALUE`.
+	Set []string
+	// Unset removes keys.
+	Unset []string
+	// Redact hides secret values, configmap and environment values are kept.
+	Redact bool
+	// RedactStyle is how secret values are hidden: mask, length, hash or prefix.
+	RedactStyle string
+	// RedactChars is the number of characters shown with the prefix redaction style.
+	RedactChars int
+	// SecretTypes converts Secrets of well-known types, such as `kubernetes.io/tls`, into conventional variables and
+	// files written with WriteFile.
+	SecretTypes bool
+	// DockerConfig writes `kubernetes.io/dockerconfigjson` Secrets as a docker config file instead of registry
+	// variables when SecretTypes is set.
+	DockerConfig bool
+	// FileDir is the absolute directory WriteFile writes to, paths to companion files are relative to it when empty.
+	FileDir string
+	// WriteFile writes companion files next to the output, it has the same signature as `os.WriteFile`.
+	WriteFile func(name string, data []byte, perm os.FileMode) error
+	// CommandWriter receives CI workflow commands such as `::add-mask::` apart from the output, defaults to standard
+	// output.
+	CommandWriter io.Writer
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidKey is returned when a key cannot be represented in the output format.
+var ErrInvalidKey = errors.New("invalid key")
+
+// ErrMultilineValue is returned when a multiline value cannot be represented in the output format.
+var ErrMultilineValue = errors.New("multiline values are not supported")
+
+const delimiterBytes = 16
+
+func gitHubDelimiter(value string) (string, error) {
+	buf := make([]byte, delimiterBytes)
+
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("generating delimiter: %w", err)
+		}
+
+		delimiter := "ghadelimiter_" + hex.EncodeToString(buf)
+		if !strings.Contains(value, delimiter) {
+			return delimiter, nil
+		}
+	}
+}
+
+// GitHubEnv builds a `$GITHUB_ENV` entry given a k/v pair, multiline values use a random heredoc delimiter.
+func GitHubEnv(key, value string) (string, error) {
+	key = strings.ReplaceAll(key, ".", "")
+	if key == "" || strings.ContainsAny(key, "=\n") || strings.Contains(key, "<<") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	if !strings.ContainsAny(value, "\r\n") {
+		return fmt.Sprintf("%s=%s\n", key, value), nil
+	}
+
+	delimiter, err := gitHubDelimiter(value)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%[1]s<<%[2]s\n%[3]s\n%[2]s\n", key, delimiter, value), nil
+}
+
+// GitHubMask builds `::add-mask::` workflow commands for a value, one per line since the runner masks line by line.
+func GitHubMask(value string) string {
+	replacer := strings.NewReplacer("%", "%25", "\r", "%0D")
+
+	var res string
+
+	for _, line := range strings.Split(value, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		res += fmt.Sprintf("::add-mask::%s\n", replacer.Replace(line))
+	}
+
+	return res
+}
+
+// GitLab builds a dotenv report entry given a k/v pair.
+//
+// GitLab does not support quoting, comments or multiline values in dotenv reports.
+func GitLab(key, value string) (string, error) {
+	key = strings.ReplaceAll(key, ".", "")
+	if !regexp.MustCompile(`^[A-Za-z0-9_]+$`).MatchString(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	if strings.ContainsAny(value, "\r\n") {
+		return "", fmt.Errorf("%w: %s", ErrMultilineValue, key)
+	}
+
+	return fmt.Sprintf("%s=%s\n", key, value), nil
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGitHubEnv(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+
+	tests := []struct {
+		name      string
+		args      args
+		want      string
+		multiline bool
+		wantErr   error
+	}{
+		{name: "single line", args: args{key: "key", value: "value"}, want: "key=value\n"},
+		{name: "multiline", args: args{key: "key", value: "a\nb"}, multiline: true},
+		{name: "invalid key", args: args{key: "a<<b", value: "value"}, wantErr: ErrInvalidKey},
+		{name: "empty key", args: args{key: ".", value: "value"}, wantErr: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GitHubEnv(tt.args.key, tt.args.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GitHubEnv() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !tt.multiline {
+				if got != tt.want {
+					t.Errorf("GitHubEnv() = %v, want %v", got, tt.want)
+				}
+
+				return
+			}
+
+			lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+			delimiter := strings.TrimPrefix(lines[0], tt.args.key+"<<")
+			if !strings.HasPrefix(delimiter, "ghadelimiter_") || lines[len(lines)-1] != delimiter {
+				t.Errorf("GitHubEnv() = %v, want heredoc with a random delimiter", got)
+			}
+
+			if value := strings.Join(lines[1:len(lines)-1], "\n"); value != tt.args.value {
+				t.Errorf("GitHubEnv() value = %v, want %v", value, tt.args.value)
+			}
+		})
+	}
+}
+
+func TestGitHubMask(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "single line", value: "secret", want: "::add-mask::secret\n"},
+		{name: "mask each line", value: "a\n\nb", want: "::add-mask::a\n::add-mask::b\n"},
+		{name: "escape percent", value: "100%", want: "::add-mask::100%25\n"},
+		{name: "skip empty values", value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GitHubMask(tt.value); got != tt.want {
+				t.Errorf("GitHubMask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitLab(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{name: "single line", args: args{key: "key", value: "value"}, want: "key=value\n"},
+		{name: "multiline", args: args{key: "key", value: "a\nb"}, wantErr: ErrMultilineValue},
+		{name: "invalid key", args: args{key: "a-b", value: "value"}, wantErr: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GitLab(tt.args.key, tt.args.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GitLab() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GitLab() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package parser
+
+import "strings"
+
+const (
+	expansionOperator = '$'
+	referenceOpener   = '('
+	referenceCloser   = ')'
+)
+
+// Expand replaces `$(VAR)` references the same way the kubelet does for a container's command and args.
+//
+// References to unknown variables are left as-is and `$$` escapes the operator, so `$$(VAR)` becomes `$(VAR)`.
+func Expand(input string, env map[string]string) string {
+	var res strings.Builder
+
+	checkpoint := 0
+
+	for cursor := 0; cursor < len(input); cursor++ {
+		if input[cursor] != expansionOperator || cursor+1 >= len(input) {
+			continue
+		}
+
+		res.WriteString(input[checkpoint:cursor])
+
+		read, isVar, advance := readVariableName(input[cursor+1:])
+		if isVar {
+			if value, found := env[read]; found {
+				res.WriteString(value)
+			} else {
+				res.WriteString("$(" + read + ")")
+			}
+		} else {
+			res.WriteString(read)
+		}
+
+		cursor += advance
+		checkpoint = cursor + 1
+	}
+
+	return res.String() + input[checkpoint:]
+}
+
+// readVariableName reads a variable name after the operator, returning what was read,
+// whether it is a variable reference and how many bytes were consumed.
+func readVariableName(input string) (string, bool, int) {
+	switch input[0] {
+	case expansionOperator:
+		return input[0:1], false, 1
+	case referenceOpener:
+		for i := 1; i < len(input); i++ {
+			if input[i] == referenceCloser {
+				return input[1:i], true, i + 1
+			}
+		}
+
+		return string(expansionOperator) + string(referenceOpener), false, 1
+	default:
+		return string(expansionOperator) + string(input[0]), false, 1
+	}
+}
+
+// ExpandAll applies `Expand` to every item.
+func ExpandAll(inputs []string, env map[string]string) []string {
+	res := make([]string, 0, len(inputs))
+
+	for _, input := range inputs {
+		res = append(res, Expand(input, env))
+	}
+
+	return res
+}
@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{"HOST": "db", "PORT": "5432"}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no references", input: "--verbose", want: "--verbose"},
+		{name: "expand reference", input: "--url=$(HOST):$(PORT)", want: "--url=db:5432"},
+		{name: "keep unknown reference", input: "$(MISSING)", want: "$(MISSING)"},
+		{name: "escape operator", input: "$$(HOST)", want: "$(HOST)"},
+		{name: "keep shell syntax", input: "$HOST ${PORT}", want: "$HOST ${PORT}"},
+		{name: "unterminated reference", input: "$(HOST", want: "$(HOST"},
+		{name: "trailing operator", input: "cost$", want: "cost$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Expand(tt.input, env); got != tt.want {
+				t.Errorf("Expand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandAll(t *testing.T) {
+	got := ExpandAll([]string{"$(A)", "b"}, map[string]string{"A": "a"})
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandAll() = %v, want %v", got, want)
+	}
+}
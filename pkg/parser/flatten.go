@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ErrNotStructured is returned when a value is not a JSON or YAML object or array.
+var ErrNotStructured = errors.New("value is not a JSON or YAML object or array")
+
+// ErrDuplicateKey is returned when different paths of a document flatten to the same key.
+var ErrDuplicateKey = errors.New("duplicate flattened key")
+
+// decodeDocument decodes a JSON document, or a YAML document when it is not valid JSON.
+func decodeDocument(document string) (interface{}, error) {
+	data := []byte(strings.TrimSpace(document))
+
+	if !json.Valid(data) {
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotStructured, err.Error())
+		}
+
+		data = converted
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var res interface{}
+	if err := decoder.Decode(&res); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotStructured, err.Error())
+	}
+
+	switch res.(type) {
+	case map[string]interface{}, []interface{}:
+		return res, nil
+	}
+
+	return nil, ErrNotStructured
+}
+
+// Flatten expands a JSON or YAML object or array into a key per nested value, named by joining prefix with the
+// object keys and array indexes on separator: `{"db":{"host":"x"}}` with the prefix `credentials` and `_` becomes
+// `CREDENTIALS_DB_HOST=x`. Names are built with `EnvKey`.
+//
+// Objects and arrays nested deeper than depth are kept as JSON, a depth of 0 expands every level.
+func Flatten(prefix, document, separator string, depth int) (map[string]string, error) {
+	node, err := decodeDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]string{}
+	if err := flattenNode(res, []string{prefix}, node, separator, depth); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func flattenNode(res map[string]string, path []string, node interface{}, separator string, depth int) error {
+	expand := depth == 0 || len(path) <= depth
+
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if expand {
+			keys := make([]string, 0, len(value))
+			for key := range value {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				if err := flattenNode(res, append(path[:len(path):len(path)], key), value[key], separator, depth); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	case []interface{}:
+		if expand {
+			for i, child := range value {
+				if err := flattenNode(res, append(path[:len(path):len(path)], strconv.Itoa(i)), child, separator, depth); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	case string:
+		return setFlat(res, path, value, separator)
+	case nil:
+		return setFlat(res, path, "", separator)
+	}
+
+	encoded, err := json.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", EnvKey(path, separator), err)
+	}
+
+	return setFlat(res, path, string(encoded), separator)
+}
+
+// setFlat sets the key of path to value, or fails when another path already flattened to the same key.
+func setFlat(res map[string]string, path []string, value, separator string) error {
+	key := EnvKey(path, separator)
+	if _, ok := res[key]; ok {
+		return fmt.Errorf("%w: %s (%s)", ErrDuplicateKey, key, strings.Join(path, "."))
+	}
+
+	res[key] = value
+
+	return nil
+}
+
+// EnvKey joins parts into an environment variable name on separator, parts are upper cased with characters other
+// than letters, digits and `_` replaced by `_`. Empty parts are skipped.
+func EnvKey(parts []string, separator string) string {
+	res := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		res = append(res, strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' {
+				return r - 'a' + 'A'
+			}
+
+			if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+				return r
+			}
+
+			return '_'
+		}, part))
+	}
+
+	return strings.Join(res, separator)
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	type args struct {
+		prefix    string
+		document  string
+		separator string
+		depth     int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "json object",
+			args: args{
+				prefix:    "credentials",
+				document:  `{"db": {"host": "db", "port": 5432, "tls": true, "user": null}, "hosts": ["a", "b"]}`,
+				separator: "_",
+			},
+			want: map[string]string{
+				"CREDENTIALS_DB_HOST": "db",
+				"CREDENTIALS_DB_PORT": "5432",
+				"CREDENTIALS_DB_TLS":  "true",
+				"CREDENTIALS_DB_USER": "",
+				"CREDENTIALS_HOSTS_0": "a",
+				"CREDENTIALS_HOSTS_1": "b",
+			},
+		},
+		{
+			name: "yaml object",
+			args: args{
+				prefix:    "config",
+				document:  "db:\n  host: db\n  pool-size: 10\n",
+				separator: "__",
+			},
+			want: map[string]string{"CONFIG__DB__HOST": "db", "CONFIG__DB__POOL_SIZE": "10"},
+		},
+		{
+			name: "json array without prefix",
+			args: args{document: ` [{"a": 1}] `, separator: "_"},
+			want: map[string]string{"0_A": "1"},
+		},
+		{
+			name: "depth limit",
+			args: args{prefix: "c", document: `{"db": {"host": "db"}, "port": 1}`, separator: "_", depth: 1},
+			want: map[string]string{"C_DB": `{"host":"db"}`, "C_PORT": "1"},
+		},
+		{
+			name:    "error on scalar",
+			args:    args{document: `"value"`, separator: "_"},
+			wantErr: ErrNotStructured,
+		},
+		{
+			name:    "error on plain text",
+			args:    args{document: "-----BEGIN CERTIFICATE-----\nMIIB", separator: "_"},
+			wantErr: ErrNotStructured,
+		},
+		{
+			name:    "error on invalid yaml",
+			args:    args{document: "a: [", separator: "_"},
+			wantErr: ErrNotStructured,
+		},
+		{
+			name:    "error on keys that differ only in case",
+			args:    args{document: `{"Host":"a","host":"b"}`, separator: "_"},
+			wantErr: ErrDuplicateKey,
+		},
+		{
+			name:    "error on paths that flatten to the same key",
+			args:    args{document: `{"a":{"b":"x"},"a_b":"y"}`, separator: "_"},
+			wantErr: ErrDuplicateKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Flatten(tt.args.prefix, tt.args.document, tt.args.separator, tt.args.depth)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Flatten() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// hclString builds an HCL quoted string, template sequences are escaped so values are used literally.
+func hclString(value string) string {
+	var res strings.Builder
+
+	res.WriteRune('"')
+
+	for i, char := range value {
+		switch char {
+		case '"':
+			res.WriteString(`\"`)
+		case '\\':
+			res.WriteString(`\\`)
+		case '\t':
+			res.WriteString(`\t`)
+		case '\n':
+			res.WriteString(`\n`)
+		case '\r':
+			res.WriteString(`\r`)
+		case '$', '%':
+			if strings.HasPrefix(value[i+1:], "{") {
+				res.WriteRune(char)
+			}
+
+			res.WriteRune(char)
+		default:
+			if char < firstPrintable || char == deleteChar {
+				fmt.Fprintf(&res, `\u%04X`, char)
+
+				continue
+			}
+
+			res.WriteRune(char)
+		}
+	}
+
+	res.WriteRune('"')
+
+	return res.String()
+}
+
+func tfVarsName(key string) error {
+	if !regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`).MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return nil
+}
+
+// TFVars builds a Terraform variable assignment, the key must be a valid variable name.
+func TFVars(key, value string) (string, error) {
+	if err := tfVarsName(key); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s = %s\n", key, hclString(value)), nil
+}
+
+// TFVarsMap builds a Terraform map variable assignment given its rendered entries.
+func TFVarsMap(name, entries string) (string, error) {
+	if err := tfVarsName(name); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s = {\n%s}\n", name, entries), nil
+}
+
+// TFVarsMapEntry builds an entry of an HCL map, the key is always quoted.
+func TFVarsMapEntry(key, value string) string {
+	return fmt.Sprintf("  %s = %s\n", hclString(key), hclString(value))
+}
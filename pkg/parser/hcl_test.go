@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTFVars(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{name: "plain value", key: "region", value: "us-east-1", want: "region = \"us-east-1\"\n"},
+		{name: "escape quotes and newlines", key: "k", value: "a\"b\\c\nd\te", want: `k = "a\"b\\c\nd\te"` + "\n"},
+		{name: "escape template sequences", key: "k", value: "${var} %{if} $5 100%", want: `k = "$${var} %%{if} $5 100%"` + "\n"},
+		{name: "escape control characters", key: "k", value: "\x01", want: `k = "\u0001"` + "\n"},
+		{name: "error on invalid name", key: "1key", value: "v", wantErr: ErrInvalidKey},
+		{name: "error on dotted name", key: "my.key", value: "v", wantErr: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TFVars(tt.key, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TFVars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("TFVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTFVarsMap(t *testing.T) {
+	got, err := TFVarsMap("env", TFVarsMapEntry("my.key", "${v}"))
+	if want := "env = {\n  \"my.key\" = \"$${v}\"\n}\n"; err != nil || got != want {
+		t.Errorf("TFVarsMap() = %v, %v, want %v", got, err, want)
+	}
+
+	if _, err := TFVarsMap("my env", ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("TFVarsMap() error = %v, wantErr %v", err, ErrInvalidKey)
+	}
+}
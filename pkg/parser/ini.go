@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// INI builds an INI key/value pair as read by Python's configparser.
+//
+// `%` is doubled for interpolation and multiline values are written as indented continuation lines.
+func INI(key, value string) (string, error) {
+	if key == "" || strings.ContainsAny(key, "=:\r\n") || strings.HasPrefix(key, "[") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	value = strings.ReplaceAll(value, "%", "%%")
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+
+	return fmt.Sprintf("%s = %s\n", key, strings.ReplaceAll(value, "\n", "\n    ")), nil
+}
+
+// INISection builds an INI section header.
+func INISection(name string) string {
+	return fmt.Sprintf("[%s]\n", strings.NewReplacer("[", "", "]", "", "\n", "").Replace(name))
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestINI(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{name: "plain value", args: args{key: "key", value: "value"}, want: "key = value\n"},
+		{name: "escape interpolation", args: args{key: "key", value: "100%"}, want: "key = 100%%\n"},
+		{name: "continuation lines", args: args{key: "key", value: "a\r\nb"}, want: "key = a\n    b\n"},
+		{name: "invalid key", args: args{key: "a=b", value: "value"}, wantErr: ErrInvalidKey},
+		{name: "section key", args: args{key: "[a", value: "value"}, wantErr: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := INI(tt.args.key, tt.args.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("INI() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("INI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestINISection(t *testing.T) {
+	tests := []struct {
+		name    string
+		section string
+		want    string
+	}{
+		{name: "section", section: "secret db", want: "[secret db]\n"},
+		{name: "strip brackets", section: "a[b]", want: "[ab]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := INISection(tt.section); got != tt.want {
+				t.Errorf("INISection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
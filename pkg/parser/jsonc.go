@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidJSONC is returned when a JSON with comments document, such as VS Code's launch.json, cannot be scanned.
+var ErrInvalidJSONC = errors.New("invalid JSON with comments")
+
+// JSONCSpan is the location of a value in a JSON with comments document.
+type JSONCSpan struct {
+	Start int
+	End   int
+}
+
+// JSONCMember is the location of an object member in a JSON with comments document.
+type JSONCMember struct {
+	Key      string
+	KeyStart int
+	Value    JSONCSpan
+}
+
+func newJSONCError(pos int) error {
+	return fmt.Errorf("%w: offset %d", ErrInvalidJSONC, pos)
+}
+
+// JSONCSkip returns the position of the next token at or after pos, skipping whitespace and comments.
+func JSONCSkip(data []byte, pos int) int {
+	for pos < len(data) {
+		switch {
+		case bytes.IndexByte([]byte(" \t\r\n"), data[pos]) != -1:
+			pos++
+		case bytes.HasPrefix(data[pos:], []byte("//")):
+			end := bytes.IndexByte(data[pos:], '\n')
+			if end == -1 {
+				return len(data)
+			}
+
+			pos += end + 1
+		case bytes.HasPrefix(data[pos:], []byte("/*")):
+			end := bytes.Index(data[pos+2:], []byte("*/"))
+			if end == -1 {
+				return len(data)
+			}
+
+			pos += end + 4 //nolint
+		default:
+			return pos
+		}
+	}
+
+	return pos
+}
+
+func jsoncStringEnd(data []byte, pos int) (int, error) {
+	for i := pos + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+
+	return 0, newJSONCError(pos)
+}
+
+// JSONCValueEnd returns the position right after the value starting at pos.
+func JSONCValueEnd(data []byte, pos int) (int, error) {
+	if pos >= len(data) {
+		return 0, newJSONCError(pos)
+	}
+
+	switch data[pos] {
+	case '"':
+		return jsoncStringEnd(data, pos)
+	case '{', '[':
+		depth := 0
+
+		for i := pos; i < len(data); i = JSONCSkip(data, i) {
+			switch data[i] {
+			case '"':
+				end, err := jsoncStringEnd(data, i)
+				if err != nil {
+					return 0, err
+				}
+
+				i = end
+
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1, nil
+				}
+			}
+
+			i++
+		}
+
+		return 0, newJSONCError(pos)
+	default:
+		end := pos
+		for end < len(data) && bytes.IndexByte([]byte(",]} \t\r\n/"), data[end]) == -1 {
+			end++
+		}
+
+		if end == pos {
+			return 0, newJSONCError(pos)
+		}
+
+		return end, nil
+	}
+}
+
+// JSONCMembers returns the members of the object starting at pos and the position of its closing brace.
+func JSONCMembers(data []byte, pos int) ([]JSONCMember, int, error) {
+	if pos >= len(data) || data[pos] != '{' {
+		return nil, 0, newJSONCError(pos)
+	}
+
+	members := []JSONCMember{}
+
+	for i := JSONCSkip(data, pos+1); i < len(data); {
+		switch data[i] {
+		case '}':
+			return members, i, nil
+		case ',':
+			i = JSONCSkip(data, i+1)
+
+			continue
+		case '"':
+		default:
+			return nil, 0, newJSONCError(i)
+		}
+
+		keyEnd, err := jsoncStringEnd(data, i)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var key string
+		if err := json.Unmarshal(data[i:keyEnd], &key); err != nil {
+			return nil, 0, newJSONCError(i)
+		}
+
+		colon := JSONCSkip(data, keyEnd)
+		if colon >= len(data) || data[colon] != ':' {
+			return nil, 0, newJSONCError(colon)
+		}
+
+		valueStart := JSONCSkip(data, colon+1)
+
+		valueEnd, err := JSONCValueEnd(data, valueStart)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		members = append(members, JSONCMember{Key: key, KeyStart: i, Value: JSONCSpan{Start: valueStart, End: valueEnd}})
+		i = JSONCSkip(data, valueEnd)
+	}
+
+	return nil, 0, newJSONCError(pos)
+}
+
+// JSONCElements returns the elements of the array starting at pos and the position of its closing bracket.
+func JSONCElements(data []byte, pos int) ([]JSONCSpan, int, error) {
+	if pos >= len(data) || data[pos] != '[' {
+		return nil, 0, newJSONCError(pos)
+	}
+
+	elements := []JSONCSpan{}
+
+	for i := JSONCSkip(data, pos+1); i < len(data); {
+		switch data[i] {
+		case ']':
+			return elements, i, nil
+		case ',':
+			i = JSONCSkip(data, i+1)
+
+			continue
+		}
+
+		end, err := JSONCValueEnd(data, i)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		elements = append(elements, JSONCSpan{Start: i, End: end})
+		i = JSONCSkip(data, end)
+	}
+
+	return nil, 0, newJSONCError(pos)
+}
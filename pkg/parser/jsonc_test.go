@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJSONCSkip(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{name: "whitespace", data: " \n\t{", want: 3},
+		{name: "line comment", data: "// c\n{", want: 5},
+		{name: "block comment", data: "/* c */{", want: 7},
+		{name: "unterminated comment", data: "/* c", want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JSONCSkip([]byte(tt.data), 0); got != tt.want {
+				t.Errorf("JSONCSkip() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONCValueEnd(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int
+		wantErr bool
+	}{
+		{name: "string", data: `"a\"b", 1`, want: 6},
+		{name: "literal", data: `true}`, want: 4},
+		{name: "nested", data: `{"a": ["}", /* ] */ 1], }, 2`, want: 25},
+		{name: "error on unterminated object", data: `{"a": 1`, wantErr: true},
+		{name: "error on unterminated string", data: `"a`, wantErr: true},
+		{name: "error on missing value", data: `,`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONCValueEnd([]byte(tt.data), 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSONCValueEnd() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("JSONCValueEnd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONCMembers(t *testing.T) {
+	data := []byte(`{"a": 1, // one
+"b": {"c": 2},}`)
+
+	got, closing, err := JSONCMembers(data, 0)
+	if err != nil {
+		t.Fatalf("JSONCMembers() error = %v", err)
+	}
+
+	want := []JSONCMember{
+		{Key: "a", KeyStart: 1, Value: JSONCSpan{Start: 6, End: 7}},
+		{Key: "b", KeyStart: 16, Value: JSONCSpan{Start: 21, End: 29}},
+	}
+
+	if !reflect.DeepEqual(got, want) || closing != 30 {
+		t.Errorf("JSONCMembers() = %v, %v, want %v, %v", got, closing, want, 30)
+	}
+
+	for _, invalid := range []string{`[]`, `{1: 2}`, `{"a" 1}`, `{"a": 1`} {
+		if _, _, err := JSONCMembers([]byte(invalid), 0); !errors.Is(err, ErrInvalidJSONC) {
+			t.Errorf("JSONCMembers(%s) error = %v, wantErr %v", invalid, err, ErrInvalidJSONC)
+		}
+	}
+}
+
+func TestJSONCElements(t *testing.T) {
+	data := []byte(`[1, "b" /* c */, {}]`)
+
+	got, closing, err := JSONCElements(data, 0)
+	if err != nil {
+		t.Fatalf("JSONCElements() error = %v", err)
+	}
+
+	want := []JSONCSpan{{Start: 1, End: 2}, {Start: 4, End: 7}, {Start: 17, End: 19}}
+	if !reflect.DeepEqual(got, want) || closing != 19 {
+		t.Errorf("JSONCElements() = %v, %v, want %v, %v", got, closing, want, 19)
+	}
+
+	for _, invalid := range []string{`{}`, `[1`, `["a]`} {
+		if _, _, err := JSONCElements([]byte(invalid), 0); !errors.Is(err, ErrInvalidJSONC) {
+			t.Errorf("JSONCElements(%s) error = %v, wantErr %v", invalid, err, ErrInvalidJSONC)
+		}
+	}
+}
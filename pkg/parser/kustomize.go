@@ -0,0 +1,8 @@
+package parser
+
+import "fmt"
+
+// Kustomize builds a line of a kustomize `envs` file given a k/v pair, kustomize does not support quoting.
+func Kustomize(key, value string) string {
+	return fmt.Sprintf("%s=%s\n", key, value)
+}
@@ -0,0 +1,27 @@
+package parser
+
+import "testing"
+
+func TestKustomize(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "keep dots", args: args{key: "k.ey", value: "value"}, want: "k.ey=value\n"},
+		{name: "do not quote", args: args{key: "key", value: "a \"b\""}, want: "key=a \"b\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Kustomize(tt.args.key, tt.args.value); got != tt.want {
+				t.Errorf("Kustomize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidValue is returned when a value cannot be represented in the output format.
+var ErrInvalidValue = errors.New("invalid value")
+
+// Make builds a Makefile variable assignment given a k/v pair, `$` is escaped as `$$` so values are used literally.
+//
+// Values make would alter in a simple assignment, such as multiline values or values with leading whitespace,
+// are written with `define`.
+func Make(shouldExport bool, key, value string) (string, error) {
+	key = strings.ReplaceAll(key, ".", "")
+	if !regexp.MustCompile(`^[^\s:#=]+$`).MatchString(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	if regexp.MustCompile(`\\(\r?\n|$)`).MatchString(value) {
+		return "", fmt.Errorf("%w: %s ends a line with a backslash", ErrInvalidValue, key)
+	}
+
+	export := ""
+	if shouldExport {
+		export = "export "
+	}
+
+	escaped := strings.ReplaceAll(value, "$", "$$")
+
+	if strings.ContainsAny(value, "\r\n") || strings.TrimLeft(value, " \t") != value {
+		res := fmt.Sprintf("define %s\n%s\nendef\n", key, escaped)
+		if shouldExport {
+			res += fmt.Sprintf("export %s\n", key)
+		}
+
+		return res, nil
+	}
+
+	return fmt.Sprintf("%s%s := %s\n", export, key, strings.ReplaceAll(escaped, "#", `\#`)), nil
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name         string
+		shouldExport bool
+		key          string
+		value        string
+		want         string
+		wantErr      error
+	}{
+		{name: "export value", shouldExport: true, key: "k", value: "v", want: "export k := v\n"},
+		{name: "no export", key: "k", value: "v", want: "k := v\n"},
+		{name: "escape dollar and hash", key: "k", value: "$HOME #1", want: "k := $$HOME \\#1\n"},
+		{name: "strip dots from key", key: "my.key", value: "v", want: "mykey := v\n"},
+		{
+			name:         "define multiline values",
+			shouldExport: true,
+			key:          "k",
+			value:        "a $b\n#c",
+			want:         "define k\na $$b\n#c\nendef\nexport k\n",
+		},
+		{name: "define leading whitespace", key: "k", value: "  v", want: "define k\n  v\nendef\n"},
+		{name: "error on invalid key", key: "a:b", value: "v", wantErr: ErrInvalidKey},
+		{name: "error on trailing backslash", key: "k", value: `C:\`, wantErr: ErrInvalidValue},
+		{name: "error on line ending with backslash", key: "k", value: "a\\\nb", wantErr: ErrInvalidValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Make(tt.shouldExport, tt.key, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Make() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Make() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
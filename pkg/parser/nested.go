@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrKeyConflict is returned when a key is used both as a value and as a parent of other keys.
+var ErrKeyConflict = errors.New("key conflict")
+
+const separatorMarker = "\x00"
+
+// Unflatten builds a nested map from flat keys by splitting each key on any of the separators,
+// `Logging__LogLevel__Default` with `__` becomes `{"Logging":{"LogLevel":{"Default":...}}}`.
+//
+// Every key that is both a value and a parent is reported in the returned error.
+func Unflatten(values map[string]string, separators []string) (map[string]interface{}, error) {
+	pairs := []string{}
+	for _, sep := range separators {
+		if sep != "" {
+			pairs = append(pairs, sep, separatorMarker)
+		}
+	}
+
+	replacer := strings.NewReplacer(pairs...)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	res := map[string]interface{}{}
+	conflicts := []string{}
+
+	for _, key := range keys {
+		parts := strings.Split(replacer.Replace(key), separatorMarker)
+		if conflict := unflattenKey(res, parts, values[key]); conflict != "" {
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s is both a value and an object)", key, conflict))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return res, fmt.Errorf("%w: %s", ErrKeyConflict, strings.Join(conflicts, ", "))
+	}
+
+	return res, nil
+}
+
+// unflattenKey sets value at the path given by parts and returns the conflicting path when it cannot.
+func unflattenKey(node map[string]interface{}, parts []string, value string) string {
+	for i, part := range parts {
+		path := strings.Join(parts[:i+1], ":")
+
+		if i == len(parts)-1 {
+			if _, isMap := node[part].(map[string]interface{}); isMap {
+				return path
+			}
+
+			node[part] = value
+
+			return ""
+		}
+
+		switch child := node[part].(type) {
+		case map[string]interface{}:
+			node = child
+		case nil:
+			next := map[string]interface{}{}
+			node[part] = next
+			node = next
+		default:
+			return path
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUnflatten(t *testing.T) {
+	type args struct {
+		values     map[string]string
+		separators []string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "double underscore",
+			args: args{
+				values:     map[string]string{"Logging__LogLevel__Default": "Info", "Logging__Console": "true", "Name": "api"},
+				separators: []string{"__"},
+			},
+			want: map[string]interface{}{
+				"Logging": map[string]interface{}{
+					"LogLevel": map[string]interface{}{"Default": "Info"},
+					"Console":  "true",
+				},
+				"Name": "api",
+			},
+		},
+		{
+			name: "multiple separators",
+			args: args{
+				values:     map[string]string{"a.b__c": "v"},
+				separators: []string{"__", ".", ""},
+			},
+			want: map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": "v"}}},
+		},
+		{
+			name: "value then object",
+			args: args{
+				values:     map[string]string{"a": "v", "a__b": "v"},
+				separators: []string{"__"},
+			},
+			want:    map[string]interface{}{"a": "v"},
+			wantErr: ErrKeyConflict,
+		},
+		{
+			name: "object then value",
+			args: args{
+				values:     map[string]string{"a.b": "v", "a__b__c": "v"},
+				separators: []string{"__", "."},
+			},
+			want:    map[string]interface{}{"a": map[string]interface{}{"b": "v"}},
+			wantErr: ErrKeyConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unflatten(tt.args.values, tt.args.separators)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unflatten() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unflatten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
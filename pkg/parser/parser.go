@@ -6,6 +6,8 @@ import (
 )
 
 // Parse builds an export statement given a k/v pair.
+//
+// Values are double quoted with `\`, `"`, newlines and carriage returns escaped, so they read back unchanged.
 func Parse(shouldExport bool, key string, value []byte) string {
 	export := ""
 	if shouldExport {
@@ -14,11 +16,16 @@ func Parse(shouldExport bool, key string, value []byte) string {
 
 	return fmt.Sprintf("%s%s=\"%s\"\n",
 		export,
-		strings.ReplaceAll(key, ".", ""),
-		strings.ReplaceAll(string(value), "\n", "\\n"),
+		Key(key),
+		strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`).Replace(string(value)),
 	)
 }
 
+// Key returns key as it is written by Parse, dots are removed since they are not allowed in shell variable names.
+func Key(key string) string {
+	return strings.ReplaceAll(key, ".", "")
+}
+
 // ParseStr builds an export statement given a k/v pair.
 func ParseStr(shouldExport bool, key, value string) string {
 	return Parse(shouldExport, key, []byte(value))
@@ -31,6 +31,14 @@ func TestParse(t *testing.T) {
 			},
 			want: "export key=\"value\"\n",
 		},
+		{
+			name: "escape values",
+			args: args{
+				key:   "app.key",
+				value: []byte("say \"hi\"\\\r\n"),
+			},
+			want: `appkey="say \"hi\"\\\r\n"` + "\n",
+		},
 	}
 
 	for _, tt := range tests {
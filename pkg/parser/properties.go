@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	firstPrintable = 0x20
+	lastPrintable  = 0x7e
+	deleteChar     = 0x7f
+)
+
+// escapeProperties escapes a key or value the same way `java.util.Properties.store` does.
+func escapeProperties(value string, isKey bool) string {
+	var res strings.Builder
+
+	for i, char := range value {
+		switch char {
+		case '\\':
+			res.WriteString(`\\`)
+		case '\t':
+			res.WriteString(`\t`)
+		case '\n':
+			res.WriteString(`\n`)
+		case '\r':
+			res.WriteString(`\r`)
+		case '\f':
+			res.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			res.WriteRune('\\')
+			res.WriteRune(char)
+		case ' ':
+			if i == 0 || isKey {
+				res.WriteRune('\\')
+			}
+
+			res.WriteRune(char)
+		default:
+			if char < firstPrintable || char > lastPrintable {
+				for _, unit := range utf16.Encode([]rune{char}) {
+					fmt.Fprintf(&res, `\u%04X`, unit)
+				}
+
+				continue
+			}
+
+			res.WriteRune(char)
+		}
+	}
+
+	return res.String()
+}
+
+// Properties builds a line of a Java .properties file given a k/v pair.
+func Properties(key, value string) string {
+	return fmt.Sprintf("%s=%s\n", escapeProperties(key, true), escapeProperties(value, false))
+}
@@ -0,0 +1,30 @@
+package parser
+
+import "testing"
+
+func TestProperties(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "plain value", args: args{key: "key", value: "value"}, want: "key=value\n"},
+		{name: "escape separators", args: args{key: "a:b=c", value: "http://host#!"}, want: "a\\:b\\=c=http\\://host\\#\\!\n"},
+		{name: "escape spaces", args: args{key: "a b", value: " a b"}, want: "a\\ b=\\ a b\n"},
+		{name: "escape control characters", args: args{key: "key", value: "a\\b\n\t"}, want: "key=a\\\\b\\n\\t\n"},
+		{name: "escape unicode", args: args{key: "key", value: "é😀"}, want: "key=\\u00E9\\uD83D\\uDE00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Properties(tt.args.key, tt.args.value); got != tt.want {
+				t.Errorf("Properties() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShellQuote quotes a value for POSIX shells, single quotes are closed, escaped and reopened.
+func ShellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// Shell builds a POSIX shell export statement given a k/v pair, the value is single quoted.
+func Shell(key, value string) string {
+	return fmt.Sprintf("export %s=%s\n", strings.ReplaceAll(key, ".", ""), ShellQuote(value))
+}
+
+// ShellCommand quotes every word of a command line.
+func ShellCommand(words []string) string {
+	quoted := make([]string, 0, len(words))
+
+	for _, word := range words {
+		quoted = append(quoted, ShellQuote(word))
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,50 @@
+package parser
+
+import "testing"
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain value", value: "value", want: "'value'"},
+		{name: "keep expansions literal", value: "$HOME `id`", want: "'$HOME `id`'"},
+		{name: "escape single quotes", value: "it's", want: `'it'\''s'`},
+		{name: "empty value", value: "", want: "''"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShellQuote(tt.value); got != tt.want {
+				t.Errorf("ShellQuote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShell(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  string
+	}{
+		{name: "export value", key: "key", value: "it's", want: "export key='it'\\''s'\n"},
+		{name: "strip dots from key", key: "my.key", value: "v", want: "export mykey='v'\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Shell(tt.key, tt.value); got != tt.want {
+				t.Errorf("Shell() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	if got, want := ShellCommand([]string{"echo", "a b"}), "'echo' 'a b'"; got != want {
+		t.Errorf("ShellCommand() = %v, want %v", got, want)
+	}
+}
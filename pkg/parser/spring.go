@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SpringKey converts an environment variable name back to a Spring property name using relaxed binding,
+// `SPRING_DATASOURCE_URL` becomes `spring.datasource.url` and `MY_LIST_0_NAME` becomes `my.list[0].name`.
+func SpringKey(key string) string {
+	index := regexp.MustCompile(`^[0-9]+$`)
+	parts := strings.Split(strings.ToLower(key), "_")
+	res := ""
+
+	for _, part := range parts {
+		switch {
+		case part == "":
+			continue
+		case index.MatchString(part) && res != "":
+			res += "[" + part + "]"
+		case res == "":
+			res = part
+		default:
+			res += "." + part
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,24 @@
+package parser
+
+import "testing"
+
+func TestSpringKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "dotted", key: "SPRING_DATASOURCE_URL", want: "spring.datasource.url"},
+		{name: "list index", key: "MY_LIST_0_NAME", want: "my.list[0].name"},
+		{name: "collapse separators", key: "A__B_", want: "a.b"},
+		{name: "leading number", key: "1_A", want: "1.a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SpringKey(tt.key); got != tt.want {
+				t.Errorf("SpringKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
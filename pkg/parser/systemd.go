@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Systemd builds an `EnvironmentFile=` assignment given a k/v pair.
+//
+// Values are double quoted, characters systemd unescapes inside double quotes are escaped and newlines are
+// kept as-is, escaping backslashes also prevents a trailing `\` from being read as a line continuation.
+func Systemd(key, value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"`", "\\`",
+		`$`, `\$`,
+	)
+
+	return fmt.Sprintf("%s=\"%s\"\n", strings.ReplaceAll(key, ".", ""), replacer.Replace(value))
+}
+
+// SystemdEnvironment builds an `Environment=` directive for a unit file given a k/v pair.
+//
+// The whole assignment is double quoted with C-style escapes and `%` is doubled so it is not read as a specifier.
+func SystemdEnvironment(key, value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`%`, `%%`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+
+	return fmt.Sprintf("Environment=\"%s=%s\"\n", strings.ReplaceAll(key, ".", ""), replacer.Replace(value))
+}
@@ -0,0 +1,63 @@
+package parser
+
+import "testing"
+
+func TestSystemd(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "plain value", args: args{key: "key", value: "value"}, want: "key=\"value\"\n"},
+		{name: "strip dots from key", args: args{key: "k.ey", value: "value"}, want: "key=\"value\"\n"},
+		{
+			name: "escape quotes and expansions",
+			args: args{key: "key", value: "a \"b\" $c `d`"},
+			want: "key=\"a \\\"b\\\" \\$c \\`d\\`\"\n",
+		},
+		{name: "escape trailing backslash", args: args{key: "key", value: `a\`}, want: "key=\"a\\\\\"\n"},
+		{name: "keep newlines", args: args{key: "key", value: "a\nb"}, want: "key=\"a\nb\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Systemd(tt.args.key, tt.args.value); got != tt.want {
+				t.Errorf("Systemd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystemdEnvironment(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "plain value", args: args{key: "key", value: "value"}, want: "Environment=\"key=value\"\n"},
+		{name: "escape specifiers", args: args{key: "key", value: "100%"}, want: "Environment=\"key=100%%\"\n"},
+		{
+			name: "escape quotes and newlines",
+			args: args{key: "key", value: "\"a\"\nb"},
+			want: "Environment=\"key=\\\"a\\\"\\nb\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SystemdEnvironment(tt.args.key, tt.args.value); got != tt.want {
+				t.Errorf("SystemdEnvironment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
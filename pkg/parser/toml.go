@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tomlString builds a TOML basic string.
+func tomlString(value string) string {
+	var res strings.Builder
+
+	res.WriteRune('"')
+
+	for _, char := range value {
+		switch char {
+		case '"':
+			res.WriteString(`\"`)
+		case '\\':
+			res.WriteString(`\\`)
+		case '\b':
+			res.WriteString(`\b`)
+		case '\t':
+			res.WriteString(`\t`)
+		case '\n':
+			res.WriteString(`\n`)
+		case '\f':
+			res.WriteString(`\f`)
+		case '\r':
+			res.WriteString(`\r`)
+		default:
+			if char < firstPrintable || char == deleteChar {
+				fmt.Fprintf(&res, `\u%04X`, char)
+
+				continue
+			}
+
+			res.WriteRune(char)
+		}
+	}
+
+	res.WriteRune('"')
+
+	return res.String()
+}
+
+// tomlKey builds a TOML key, bare keys are used when possible and quoted otherwise.
+func tomlKey(key string) string {
+	if regexp.MustCompile(`^[A-Za-z0-9_-]+$`).MatchString(key) {
+		return key
+	}
+
+	return tomlString(key)
+}
+
+// TOML builds a TOML key/value pair.
+//
+// When dotted is set, every `.` separated part of the key is a table, otherwise the key is quoted as needed.
+func TOML(key, value string, dotted bool) string {
+	if !dotted {
+		return fmt.Sprintf("%s = %s\n", tomlKey(key), tomlString(value))
+	}
+
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = tomlKey(part)
+	}
+
+	return fmt.Sprintf("%s = %s\n", strings.Join(parts, "."), tomlString(value))
+}
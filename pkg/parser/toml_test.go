@@ -0,0 +1,34 @@
+package parser
+
+import "testing"
+
+func TestTOML(t *testing.T) {
+	type args struct {
+		key    string
+		value  string
+		dotted bool
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "bare key", args: args{key: "KEY_1", value: "value"}, want: "KEY_1 = \"value\"\n"},
+		{name: "quote keys with dots", args: args{key: "app.conf", value: "value"}, want: "\"app.conf\" = \"value\"\n"},
+		{name: "dotted keys", args: args{key: "a.b c", value: "value", dotted: true}, want: "a.\"b c\" = \"value\"\n"},
+		{
+			name: "escape values",
+			args: args{key: "key", value: "\"a\\b\"\n\x01"},
+			want: "key = \"\\\"a\\\\b\\\"\\n\\u0001\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TOML(tt.args.key, tt.args.value, tt.args.dotted); got != tt.want {
+				t.Errorf("TOML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Package process runs a child process in the foreground, forwarding signals to it.
+package process
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+)
+
+// ErrMissingCommand is returned when no command is given.
+var ErrMissingCommand = errors.New("missing command")
+
+// Options configures how a process is run.
+type Options struct {
+	Env    []string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Run starts command, forwards the signals received while it runs and returns its exit code.
+// The error is only set when the command cannot be started.
+func Run(command []string, opt Options) (int, error) {
+	if len(command) == 0 {
+		return 0, ErrMissingCommand
+	}
+
+	path, err := exec.LookPath(command[0])
+	if err != nil {
+		return 0, fmt.Errorf("finding command: %w", err)
+	}
+
+	cmd := exec.Command(path, command[1:]...) //nolint
+	cmd.Env = opt.Env
+	cmd.Stdin = opt.Stdin
+	cmd.Stdout = opt.Stdout
+	cmd.Stderr = opt.Stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals()...)
+
+	if err := cmd.Start(); err != nil {
+		signal.Stop(signals)
+
+		return 0, fmt.Errorf("starting command: %w", err)
+	}
+
+	go func() {
+		for sig := range signals {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+
+	signal.Stop(signals)
+	close(signals)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitCode(exitErr), nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("running command: %w", err)
+	}
+
+	return 0, nil
+}
+
+// Environ merges values over base, a list of `KEY=value` entries such as `os.Environ()`.
+func Environ(base []string, values map[string]string) []string {
+	res := make([]string, 0, len(base)+len(values))
+
+	for _, entry := range base {
+		key := entry
+		if index := strings.IndexByte(entry, '='); index > 0 {
+			key = entry[:index]
+		}
+
+		if _, found := values[key]; !found {
+			res = append(res, entry)
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		res = append(res, key+"="+values[key])
+	}
+
+	return res
+}
+
+// ExitError is returned by commands that exit with a given code, such as the exit code of a child process,
+// without printing an error.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
@@ -0,0 +1,69 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tests := []struct {
+		name      string
+		command   []string
+		want      int
+		wantOut   string
+		wantErr   bool
+		wantErrIs error
+	}{
+		{name: "missing command", wantErrIs: ErrMissingCommand, wantErr: true},
+		{name: "unknown command", command: []string{"k8s-dotenv-missing-command"}, wantErr: true},
+		{name: "environment", command: []string{"sh", "-c", "printf %s \"$K\""}, wantOut: "v"},
+		{name: "exit code", command: []string{"sh", "-c", "exit 3"}, want: 3},
+		{name: "signal", command: []string{"sh", "-c", "kill -TERM $$"}, want: 143},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			got, err := Run(tt.command, Options{Env: []string{"K=v"}, Stdout: &out})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if got != tt.want {
+				t.Errorf("Run() = %d, want %d", got, tt.want)
+			}
+
+			if out.String() != tt.wantOut {
+				t.Errorf("Run() output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	got := Environ([]string{"PATH=/bin", "K=old", "EMPTY"}, map[string]string{"K": "new", "A": "1"})
+	want := []string{"PATH=/bin", "EMPTY", "A=1", "K=new"}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Environ() = %v", cmp.Diff(want, got))
+	}
+}
+
+func TestExitError_Error(t *testing.T) {
+	if got := (&ExitError{Code: 2}).Error(); got != "exit status 2" {
+		t.Errorf("ExitError.Error() = %q, want %q", got, "exit status 2")
+	}
+}
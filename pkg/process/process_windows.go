@@ -0,0 +1,19 @@
+//go:build windows
+
+package process
+
+import (
+	"os"
+	"os/exec"
+)
+
+// forwardedSignals are relayed to the child process. The console already sends Ctrl+C to every process attached
+// to it, so it is only caught to keep k8s-dotenv running until the child exits.
+func forwardedSignals() []os.Signal {
+	return []os.Signal{os.Interrupt}
+}
+
+// exitCode returns the exit code of the child.
+func exitCode(err *exec.ExitError) int {
+	return err.ExitCode()
+}
@@ -0,0 +1,146 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Write modes used when the output file already exists.
+const (
+	// ModeAppend adds the output to the end of the file.
+	ModeAppend = "append"
+	// ModeOverwrite replaces the file with the output.
+	ModeOverwrite = "overwrite"
+	// ModeManaged replaces only the block written by a previous run for the same resource.
+	ModeManaged = "managed"
+)
+
+// ErrUnsupportedMode is returned when the write mode is unknown or cannot be used with the output format.
+var ErrUnsupportedMode = errors.New("unsupported write mode")
+
+// ErrUnterminatedBlock is returned when a managed block has a begin marker but no end marker.
+var ErrUnterminatedBlock = errors.New("unterminated managed block")
+
+// ErrMissingBlock is returned when a file has managed blocks but none for the resource.
+var ErrMissingBlock = errors.New("no managed block for the resource")
+
+// blockBegin starts the begin marker of every managed block.
+const blockBegin = "# BEGIN k8s-dotenv "
+
+// managedFormats are the line-oriented formats that support `#` comments anywhere in the file, so blocks for several
+// resources can be written to one file. Formats such as toml or k8s are left out since two blocks would repeat
+// top-level keys or tables and the file would no longer parse.
+func managedFormats() map[string]bool {
+	return map[string]bool{
+		"docker":     true,
+		"dotenv":     true,
+		"make":       true,
+		"properties": true,
+		"systemd":    true,
+	}
+}
+
+// Modes returns the supported write modes.
+func Modes() []string {
+	return []string{ModeAppend, ModeOverwrite, ModeManaged}
+}
+
+// WriteMode validates mode for format, an empty mode selects the default of the format: managed when
+// supported, append for github-actions since the file is shared with other steps and overwrite otherwise.
+// Formats that update the output file in place are always overwritten.
+func WriteMode(format, mode string) (string, error) {
+	if format == "" {
+		format = "dotenv"
+	}
+
+	switch {
+	case UpdatesFile(format):
+		return ModeOverwrite, nil
+	case mode == "" && format == "github-actions":
+		return ModeAppend, nil
+	case mode == "" && managedFormats()[format]:
+		return ModeManaged, nil
+	case mode == "":
+		return ModeOverwrite, nil
+	case mode == ModeManaged && !managedFormats()[format]:
+		return "", fmt.Errorf("%w: %s cannot be used with the %s format", ErrUnsupportedMode, mode, format)
+	case mode == ModeAppend, mode == ModeOverwrite, mode == ModeManaged:
+		return mode, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedMode, mode)
+}
+
+func blockMarkers(resource string) (string, string) {
+	return blockBegin + resource, "# END k8s-dotenv " + resource
+}
+
+// BlockContent returns the lines between the markers of the block written for resource in content, or the whole
+// content when it has no managed blocks, such as a file written with the append or overwrite mode.
+func BlockContent(content, resource string) (string, error) {
+	begin, end := blockMarkers(resource)
+	lines := strings.SplitAfter(content, "\n")
+	start := -1
+	managed := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, " \t\r\n")
+
+		switch {
+		case trimmed == begin && start == -1:
+			start = i
+		case trimmed == end && start != -1:
+			return strings.Join(lines[start+1:i], ""), nil
+		case strings.HasPrefix(trimmed, blockBegin):
+			managed = true
+		}
+	}
+
+	if start != -1 {
+		return "", fmt.Errorf("%w: %s", ErrUnterminatedBlock, resource)
+	}
+
+	if managed {
+		return "", fmt.Errorf("%w: %s", ErrMissingBlock, resource)
+	}
+
+	return content, nil
+}
+
+// ManagedBlock replaces the block written for resource in original with content, keeping every other line.
+// The block is added to the end of the file when there is none.
+func ManagedBlock(original, content, resource string) (string, error) {
+	begin, end := blockMarkers(resource)
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	block := begin + "\n" + content + end + "\n"
+	lines := strings.SplitAfter(original, "\n")
+	start := -1
+
+	for i, line := range lines {
+		switch strings.TrimRight(line, " \t\r\n") {
+		case begin:
+			if start == -1 {
+				start = i
+			}
+		case end:
+			if start != -1 {
+				return strings.Join(lines[:start], "") + block + strings.Join(lines[i+1:], ""), nil
+			}
+		}
+	}
+
+	if start != -1 {
+		return "", fmt.Errorf("%w: %s", ErrUnterminatedBlock, resource)
+	}
+
+	if original != "" && !strings.HasSuffix(original, "\n") {
+		original += "\n"
+	}
+
+	return original + block, nil
+}
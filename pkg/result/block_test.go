@@ -0,0 +1,146 @@
+package result
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWriteMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		mode    string
+		want    string
+		wantErr error
+	}{
+		{name: "default managed", format: "", want: ModeManaged},
+		{name: "default append for github-actions", format: "github-actions", want: ModeAppend},
+		{name: "default overwrite", format: "nested-json", want: ModeOverwrite},
+		{name: "always overwrite files updated in place", format: "vscode", mode: ModeAppend, want: ModeOverwrite},
+		{name: "explicit mode", format: "dotenv", mode: ModeAppend, want: ModeAppend},
+		{name: "error on managed without comments", format: "nested-json", mode: ModeManaged, wantErr: ErrUnsupportedMode},
+		{name: "default overwrite for structured formats", format: "toml", want: ModeOverwrite},
+		{name: "error on managed structured formats", format: "k8s", mode: ModeManaged, wantErr: ErrUnsupportedMode},
+		{name: "error on unknown mode", format: "dotenv", mode: "merge", wantErr: ErrUnsupportedMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WriteMode(tt.format, tt.mode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WriteMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("WriteMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagedBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		content  string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:    "new file",
+			content: "A=1\n",
+			want:    "# BEGIN k8s-dotenv deployment/api\nA=1\n# END k8s-dotenv deployment/api\n",
+		},
+		{
+			name:     "append block",
+			original: "USER=me",
+			content:  "A=1",
+			want:     "USER=me\n# BEGIN k8s-dotenv deployment/api\nA=1\n# END k8s-dotenv deployment/api\n",
+		},
+		{
+			name: "replace block",
+			original: `USER=me
+# BEGIN k8s-dotenv deployment/api
+A=0
+# END k8s-dotenv deployment/api
+# BEGIN k8s-dotenv deployment/web
+B=0
+# END k8s-dotenv deployment/web
+OTHER=1
+`,
+			content: "A=1\n",
+			want: `USER=me
+# BEGIN k8s-dotenv deployment/api
+A=1
+# END k8s-dotenv deployment/api
+# BEGIN k8s-dotenv deployment/web
+B=0
+# END k8s-dotenv deployment/web
+OTHER=1
+`,
+		},
+		{
+			name:     "error on unterminated block",
+			original: "# BEGIN k8s-dotenv deployment/api\nA=0\n",
+			content:  "A=1\n",
+			wantErr:  ErrUnterminatedBlock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ManagedBlock(tt.original, tt.content, "deployment/api")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ManagedBlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ManagedBlock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockContent(t *testing.T) {
+	shared := `USER=me
+# BEGIN k8s-dotenv deployment/web
+B=0
+# END k8s-dotenv deployment/web
+# BEGIN k8s-dotenv deployment/api
+A=0
+# END k8s-dotenv deployment/api
+`
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr error
+	}{
+		{name: "block of the resource", content: shared, want: "A=0\n"},
+		{name: "whole file without blocks", content: "A=0\nB=0\n", want: "A=0\nB=0\n"},
+		{
+			name:    "error without a block for the resource",
+			content: "# BEGIN k8s-dotenv deployment/web\nB=0\n# END k8s-dotenv deployment/web\n",
+			wantErr: ErrMissingBlock,
+		},
+		{
+			name:    "error on unterminated block",
+			content: "# BEGIN k8s-dotenv deployment/api\nA=0\n",
+			wantErr: ErrUnterminatedBlock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BlockContent(tt.content, "deployment/api")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BlockContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("BlockContent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package result
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+// parseGitHubActions renders a `$GITHUB_ENV` file and masks every secret value.
+//
+// Masks are always sent to the command writer, standard output by default, so they reach the runner instead of the
+// env file which the runner rejects when it holds workflow commands.
+func (r *Result) parseGitHubActions() (string, error) {
+	var masks, res string
+
+	err := r.each(func(kind, name, key, value string) error {
+		if kind == KindSecret {
+			masks += parser.GitHubMask(value)
+		}
+
+		line, err := parser.GitHubEnv(key, value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", sourceName(kind, name), err)
+		}
+
+		res += line
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	commands := r.output.CommandWriter
+	if commands == nil {
+		commands = os.Stdout
+	}
+
+	if _, err := commands.Write([]byte(masks)); err != nil {
+		return "", newWriteError(err)
+	}
+
+	return res, nil
+}
+
+// parseGitLab renders a dotenv report for `artifacts:reports:dotenv`.
+func (r *Result) parseGitLab() (string, error) {
+	var res string
+
+	err := r.each(func(kind, name, key, value string) error {
+		line, err := parser.GitLab(key, value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", sourceName(kind, name), err)
+		}
+
+		res += line
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
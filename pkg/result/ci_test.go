@@ -0,0 +1,108 @@
+package result
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+)
+
+func TestResult_parseGitHubActions(t *testing.T) {
+	tests := []struct {
+		name         string
+		r            *Result
+		want         string
+		wantCommands string
+		wantErr      bool
+	}{
+		{
+			name: "never inline masks",
+			r: &Result{
+				output:      options.Output{CommandWriter: &bytes.Buffer{}},
+				Environment: EnvValues{"env": "val"},
+				Secrets:     map[string]EnvValues{"test": {"sec": "secret"}},
+			},
+			want:         "env=val\nsec=secret\n",
+			wantCommands: "::add-mask::secret\n",
+		},
+		{
+			name: "masks to command writer",
+			r: &Result{
+				output:     options.Output{CommandWriter: &bytes.Buffer{}},
+				ConfigMaps: map[string]EnvValues{"test": {"cm": "val"}},
+				Secrets:    map[string]EnvValues{"test": {"sec": "secret"}},
+			},
+			want:         "cm=val\nsec=secret\n",
+			wantCommands: "::add-mask::secret\n",
+		},
+		{
+			name: "return command writer errors",
+			r: &Result{
+				output:  options.Output{CommandWriter: mock.NewErrorWriter().ErrorAfter(1)},
+				Secrets: map[string]EnvValues{"test": {"sec": "secret"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "return key errors",
+			r:       &Result{Environment: EnvValues{"a<<b": "val"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseGitHubActions()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Result.parseGitHubActions() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Result.parseGitHubActions() = %v, want %v", got, tt.want)
+			}
+			if buf, ok := tt.r.output.CommandWriter.(*bytes.Buffer); ok && buf.String() != tt.wantCommands {
+				t.Errorf("Result.parseGitHubActions() commands = %v, want %v", buf.String(), tt.wantCommands)
+			}
+		})
+	}
+}
+
+func TestResult_parseGitLab(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "dotenv report",
+			r: &Result{
+				Environment: EnvValues{"env": "val"},
+				ConfigMaps:  map[string]EnvValues{"test": {"cm": "val"}},
+				Secrets:     map[string]EnvValues{"test": {"sec": "val"}},
+			},
+			want: "env=val\ncm=val\nsec=val\n",
+		},
+		{
+			name:    "error on multiline values",
+			r:       &Result{Secrets: map[string]EnvValues{"test": {"sec": "a\nb"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseGitLab()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Result.parseGitLab() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Result.parseGitLab() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package result
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+// configKey returns the key used by config file formats, converted to a Spring property name when enabled.
+func (r *Result) configKey(key string) string {
+	if r.output.SpringKeys {
+		return parser.SpringKey(key)
+	}
+
+	return key
+}
+
+func configHeader(kind, name string) string {
+	return fmt.Sprintf("# %s - %s\n", kind, name)
+}
+
+// parseProperties renders a Java .properties file.
+func (r *Result) parseProperties() (string, error) {
+	return r.lines(configHeader, func(key, value string) string {
+		return parser.Properties(r.configKey(key), value)
+	}), nil
+}
+
+// parseTOML renders a TOML document.
+//
+// TOML does not allow a key to be defined twice, so only the last value of a key is kept,
+// matching what sourcing a .env file would do. With Spring keys, a key cannot also be a table
+// for other keys, so such keys are reported as a conflict.
+func (r *Result) parseTOML() (string, error) {
+	var res, current string
+
+	keys := map[string]bool{}
+
+	_ = r.eachLast(r.configKey, func(kind, name, key, value string) error {
+		if source := sourceName(kind, name); kind != "" && source != current {
+			res += configHeader(kind, name)
+			current = source
+		}
+
+		keys[r.configKey(key)] = true
+		res += parser.TOML(r.configKey(key), value, r.output.SpringKeys)
+
+		return nil
+	})
+
+	if r.output.SpringKeys {
+		if err := tomlConflicts(keys); err != nil {
+			return "", err
+		}
+	}
+
+	return res, nil
+}
+
+// tomlConflicts returns an error listing the dotted keys that are also a table for other keys.
+func tomlConflicts(keys map[string]bool) error {
+	conflicts := []string{}
+
+	for key := range keys {
+		parts := strings.Split(key, ".")
+		for i := 1; i < len(parts); i++ {
+			if prefix := strings.Join(parts[:i], "."); keys[prefix] {
+				conflicts = append(conflicts, fmt.Sprintf("%s (%s is both a value and a table)", key, prefix))
+
+				break
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+
+		return fmt.Errorf("%w: %s", parser.ErrKeyConflict, strings.Join(conflicts, ", "))
+	}
+
+	return nil
+}
+
+// parseINI renders an INI file with a section for the environment and for each configmap and secret.
+func (r *Result) parseINI() (string, error) {
+	var res, current string
+
+	err := r.each(func(kind, name, key, value string) error {
+		if source := sourceName(kind, name); source != current {
+			if current != "" {
+				res += "\n"
+			}
+
+			res += parser.INISection(source)
+			current = source
+		}
+
+		line, err := parser.INI(r.configKey(key), value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", current, err)
+		}
+
+		res += line
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,128 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+func TestResult_parseProperties(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Result
+		want string
+	}{
+		{
+			name: "properties",
+			r: &Result{
+				Environment: EnvValues{"SERVER_PORT": "8080"},
+				Secrets:     map[string]EnvValues{"db": {"SPRING_DATASOURCE_URL": "jdbc:x"}},
+			},
+			want: "SERVER_PORT=8080\n# SECRET - db\nSPRING_DATASOURCE_URL=jdbc\\:x\n",
+		},
+		{
+			name: "spring keys",
+			r: &Result{
+				output:      options.Output{SpringKeys: true},
+				Environment: EnvValues{"SERVER_PORT": "8080"},
+			},
+			want: "server.port=8080\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := tt.r.parseProperties(); got != tt.want {
+				t.Errorf("Result.parseProperties() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_parseTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr error
+	}{
+		{
+			name: "keep the value the container sees for duplicate keys",
+			r: &Result{
+				Environment: EnvValues{"A": "env", "B": "env"},
+				ConfigMaps:  map[string]EnvValues{"app": {"A": "cm"}},
+				Secrets:     map[string]EnvValues{"db": {"A": "secret", "C": "secret"}},
+			},
+			want: "A = \"env\"\nB = \"env\"\n# SECRET - db\nC = \"secret\"\n",
+		},
+		{
+			name: "spring keys",
+			r: &Result{
+				output:      options.Output{SpringKeys: true},
+				Environment: EnvValues{"SERVER_PORT": "8080"},
+			},
+			want: "server.port = \"8080\"\n",
+		},
+		{
+			name: "error on spring keys that are both a value and a table",
+			r: &Result{
+				output:      options.Output{SpringKeys: true},
+				Environment: EnvValues{"SPRING_DATASOURCE": "ds", "SPRING_DATASOURCE_URL": "jdbc:x"},
+			},
+			wantErr: parser.ErrKeyConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseTOML()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.parseTOML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Result.parseTOML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_parseINI(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "sections",
+			r: &Result{
+				Environment: EnvValues{"env": "val"},
+				ConfigMaps:  map[string]EnvValues{"app": {"cm": "val"}},
+				Secrets:     map[string]EnvValues{"db": {"sec": "val"}},
+			},
+			want: "[environment]\nenv = val\n\n[configmap app]\ncm = val\n\n[secret db]\nsec = val\n",
+		},
+		{
+			name:    "return key errors",
+			r:       &Result{Environment: EnvValues{"a=b": "val"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseINI()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Result.parseINI() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Result.parseINI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
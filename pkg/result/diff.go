@@ -0,0 +1,154 @@
+package result
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+// Change types.
+const (
+	// ChangeAdded is a key only found in the new result.
+	ChangeAdded = "added"
+	// ChangeRemoved is a key only found in the old result.
+	ChangeRemoved = "removed"
+	// ChangeModified is a key with a different value in each result.
+	ChangeModified = "modified"
+)
+
+// Change is a key whose value differs between two results.
+type Change struct {
+	Type string
+	Key  string
+	Old  string
+	New  string
+	// Secret is set when the old or the new value was loaded from a secret.
+	Secret bool
+}
+
+// secretKeys returns the keys whose value is loaded from a secret once sources are resolved, see `precedence`.
+func (r *Result) secretKeys() map[string]bool {
+	res := map[string]bool{}
+
+	_ = r.eachLast(func(key string) string { return key }, func(kind, name, key, value string) error {
+		res[key] = kind == KindSecret
+
+		return nil
+	})
+
+	return res
+}
+
+// DotenvKeys returns a copy of the result with keys as the dotenv format writes them, so it can be compared with a
+// .env file read back. When several keys are written the same way the last one in the file wins.
+func (r *Result) DotenvKeys() *Result {
+	normalize := func(values EnvValues) EnvValues {
+		res := EnvValues{}
+
+		for _, key := range values.sortedKeys() {
+			res[parser.Key(key)] = values[key]
+		}
+
+		return res
+	}
+
+	res := *r
+	res.Environment = normalize(r.Environment)
+	res.ConfigMaps = make(map[string]EnvValues, len(r.ConfigMaps))
+	res.Secrets = make(map[string]EnvValues, len(r.Secrets))
+
+	for name, values := range r.ConfigMaps {
+		res.ConfigMaps[name] = normalize(values)
+	}
+
+	for name, values := range r.Secrets {
+		res.Secrets[name] = normalize(values)
+	}
+
+	return &res
+}
+
+// Diff returns the keys that differ between the environments of from and to, sorted by key.
+func Diff(from, to *Result) []Change {
+	oldValues, newValues := from.environment(), to.environment()
+	oldSecrets, newSecrets := from.secretKeys(), to.secretKeys()
+	res := []Change{}
+
+	for key, value := range oldValues {
+		newValue, found := newValues[key]
+
+		switch {
+		case !found:
+			res = append(res, Change{Type: ChangeRemoved, Key: key, Old: value, Secret: oldSecrets[key]})
+		case newValue != value:
+			res = append(res, Change{
+				Type:   ChangeModified,
+				Key:    key,
+				Old:    value,
+				New:    newValue,
+				Secret: oldSecrets[key] || newSecrets[key],
+			})
+		}
+	}
+
+	for key, value := range newValues {
+		if _, found := oldValues[key]; !found {
+			res = append(res, Change{Type: ChangeAdded, Key: key, New: value, Secret: newSecrets[key]})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+
+	return res
+}
+
+// diffValue returns value as shown in a diff, secret values are redacted when output.Redact is set.
+func diffValue(value string, secret bool, output options.Output) (string, error) {
+	if secret && output.Redact {
+		return redactValue(value, output.RedactStyle, output.RedactChars)
+	}
+
+	if strings.ContainsAny(value, "\n\r\t") {
+		return strconv.Quote(value), nil
+	}
+
+	return value, nil
+}
+
+// WriteChanges writes a line per change: `+ KEY=value` when added, `- KEY=value` when removed
+// and `~ KEY=old -> new` when modified.
+func WriteChanges(writer io.Writer, changes []Change, output options.Output) error {
+	var res strings.Builder
+
+	for _, change := range changes {
+		oldValue, err := diffValue(change.Old, change.Secret, output)
+		if err != nil {
+			return err
+		}
+
+		newValue, err := diffValue(change.New, change.Secret, output)
+		if err != nil {
+			return err
+		}
+
+		switch change.Type {
+		case ChangeAdded:
+			fmt.Fprintf(&res, "+ %s=%s\n", change.Key, newValue)
+		case ChangeRemoved:
+			fmt.Fprintf(&res, "- %s=%s\n", change.Key, oldValue)
+		default:
+			fmt.Fprintf(&res, "~ %s=%s -> %s\n", change.Key, oldValue, newValue)
+		}
+	}
+
+	if _, err := io.WriteString(writer, res.String()); err != nil {
+		return newWriteError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package result
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	from := &Result{
+		Environment: EnvValues{"same": "v", "removed": "v", "modified": "old"},
+		Secrets:     map[string]EnvValues{"s": {"token": "old"}},
+	}
+	to := &Result{
+		Environment: EnvValues{"same": "v", "modified": "new", "token": "new"},
+		ConfigMaps:  map[string]EnvValues{"cm": {"added": "v"}},
+		Secrets:     map[string]EnvValues{"s": {"password": "new"}},
+	}
+
+	want := []Change{
+		{Type: ChangeAdded, Key: "added", New: "v"},
+		{Type: ChangeModified, Key: "modified", Old: "old", New: "new"},
+		{Type: ChangeAdded, Key: "password", New: "new", Secret: true},
+		{Type: ChangeRemoved, Key: "removed", Old: "v"},
+		{Type: ChangeModified, Key: "token", Old: "old", New: "new", Secret: true},
+	}
+
+	if got := Diff(from, to); !cmp.Equal(got, want) {
+		t.Errorf("Diff() = %v", cmp.Diff(want, got))
+	}
+
+	if got := Diff(to, to); len(got) != 0 {
+		t.Errorf("Diff() = %v, want no changes", got)
+	}
+}
+
+func TestWriteChanges(t *testing.T) {
+	changes := []Change{
+		{Type: ChangeAdded, Key: "a", New: "line1\nline2"},
+		{Type: ChangeRemoved, Key: "b", Old: "v"},
+		{Type: ChangeModified, Key: "c", Old: "secret", New: "changed", Secret: true},
+	}
+
+	tests := []struct {
+		name    string
+		output  options.Output
+		writer  io.Writer
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "plain",
+			writer: mock.NewWriter(),
+			want:   "+ a=\"line1\\nline2\"\n- b=v\n~ c=secret -> changed\n",
+		},
+		{
+			name:   "redacted",
+			output: options.Output{Redact: true, RedactStyle: RedactLength},
+			writer: mock.NewWriter(),
+			want:   "+ a=\"line1\\nline2\"\n- b=v\n~ c=********(6) -> ********(7)\n",
+		},
+		{
+			name:    "unsupported redaction",
+			output:  options.Output{Redact: true, RedactStyle: "unknown"},
+			writer:  mock.NewWriter(),
+			wantErr: ErrUnsupportedRedaction,
+		},
+		{name: "writer error", writer: mock.NewErrorWriter(), wantErr: mock.ErrWriter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WriteChanges(tt.writer, changes, tt.output)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WriteChanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got := tt.writer.(*mock.Writer).String(); got != tt.want {
+				t.Errorf("WriteChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_DotenvKeys(t *testing.T) {
+	r := &Result{
+		Environment: EnvValues{"app.name": "api", "ab": "1", "a.b": "2"},
+		ConfigMaps:  map[string]EnvValues{"cm": {"log.level": "debug"}},
+		Secrets:     map[string]EnvValues{"sec": {"db.password": "secret"}},
+	}
+	want := &Result{
+		Environment: EnvValues{"appname": "api", "ab": "1"},
+		ConfigMaps:  map[string]EnvValues{"cm": {"loglevel": "debug"}},
+		Secrets:     map[string]EnvValues{"sec": {"dbpassword": "secret"}},
+	}
+
+	if got := r.DotenvKeys(); !cmp.Equal(got, want, cmp.AllowUnexported(Result{}, source{})) {
+		t.Errorf("Result.DotenvKeys() = %v", cmp.Diff(want, got, cmp.AllowUnexported(Result{}, source{})))
+	}
+}
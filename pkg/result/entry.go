@@ -0,0 +1,34 @@
+package result
+
+// KindEnvironment is the kind of entries set directly on a container.
+const KindEnvironment = "ENVIRONMENT"
+
+// Entry is a single value with the kind (ENVIRONMENT, CONFIGMAP or SECRET) and name of the source it was loaded from.
+type Entry struct {
+	Kind   string
+	Source string
+	Key    string
+	Value  string
+}
+
+// IsSecret reports whether the entry was loaded from a secret.
+func (e Entry) IsSecret() bool {
+	return e.Kind == KindSecret
+}
+
+// Entries returns every value in the result, environment values first followed by configmaps and secrets.
+func (r *Result) Entries() []Entry {
+	res := []Entry{}
+
+	_ = r.each(func(kind, name, key, value string) error {
+		if kind == "" {
+			kind = KindEnvironment
+		}
+
+		res = append(res, Entry{Kind: kind, Source: name, Key: key, Value: value})
+
+		return nil
+	})
+
+	return res
+}
@@ -0,0 +1,56 @@
+package result
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResult_Entries(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Result
+		want []Entry
+	}{
+		{
+			name: "entries",
+			r: &Result{
+				Environment: EnvValues{"env": "val"},
+				ConfigMaps:  map[string]EnvValues{"app": {"cm": "val"}},
+				Secrets:     map[string]EnvValues{"db": {"sec": "val"}},
+			},
+			want: []Entry{
+				{Kind: KindEnvironment, Key: "env", Value: "val"},
+				{Kind: KindConfigMap, Source: "app", Key: "cm", Value: "val"},
+				{Kind: KindSecret, Source: "db", Key: "sec", Value: "val"},
+			},
+		},
+		{name: "empty", r: newResult(), want: []Entry{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Entries(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Result.Entries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntry_IsSecret(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		want  bool
+	}{
+		{name: "secret", entry: Entry{Kind: KindSecret}, want: true},
+		{name: "configmap", entry: Entry{Kind: KindConfigMap}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.IsSecret(); got != tt.want {
+				t.Errorf("Entry.IsSecret() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+const defaultExpandSeparator = "_"
+
+// expandPrefix is the name expanded keys start with, the key without a `.json`, `.yaml` or `.yml` extension.
+func expandPrefix(key string) string {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".json", ".yaml", ".yml":
+		return strings.TrimSuffix(key, path.Ext(key))
+	}
+
+	return key
+}
+
+// expand replaces the values of keys in the `--expand` allow-list that hold a JSON or YAML object or array with a key
+// per nested value, such as `CREDENTIALS_DB_HOST` for `credentials.json`. Other values are kept as they are.
+// Documents with several paths that map to the same key are an error.
+func (f *filters) expand(kind string, values EnvValues) (EnvValues, error) {
+	if len(f.expandKeys) == 0 {
+		return values, nil
+	}
+
+	res := make(EnvValues, len(values))
+
+	for _, key := range values.sortedKeys() {
+		if allowed, _ := matches(f.expandKeys, kind, key); allowed {
+			flat, err := parser.Flatten(expandPrefix(key), values[key], f.expandSeparator, f.expandDepth)
+			if errors.Is(err, parser.ErrDuplicateKey) {
+				return nil, fmt.Errorf("expanding %s: %w", key, err)
+			}
+
+			if err == nil {
+				for flatKey, value := range flat {
+					res[flatKey] = value
+				}
+
+				continue
+			}
+		}
+
+		res[key] = values[key]
+	}
+
+	return res, nil
+}
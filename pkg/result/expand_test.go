@@ -0,0 +1,24 @@
+package result
+
+import "testing"
+
+func Test_expandPrefix(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "credentials.json", want: "credentials"},
+		{key: "config.YAML", want: "config"},
+		{key: "values.yml", want: "values"},
+		{key: "settings", want: "settings"},
+		{key: "cert.pem", want: "cert.pem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := expandPrefix(tt.key); got != tt.want {
+				t.Errorf("expandPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
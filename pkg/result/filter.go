@@ -0,0 +1,278 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+)
+
+// ErrInvalidFilter is returned when a key filter, rename or override cannot be parsed.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// filterKinds are the prefixes that limit a filter to the values of one kind of source, such as `secret:DB_*`.
+func filterKinds() map[string]string {
+	return map[string]string{
+		"env":       KindEnvironment,
+		"configmap": KindConfigMap,
+		"secret":    KindSecret,
+	}
+}
+
+// keyPattern matches keys of one kind of source, or of every source when kind is empty.
+type keyPattern struct {
+	kind  string
+	match func(key string) bool
+}
+
+// parsePattern parses a glob such as `DB_*`, or a regular expression between slashes such as `/^DB_(HOST|PORT)$/`,
+// optionally prefixed with the kind of source it applies to.
+func parsePattern(pattern string) (keyPattern, error) {
+	var res keyPattern
+
+	if prefix, rest, found := strings.Cut(pattern, ":"); found {
+		if kind, known := filterKinds()[strings.ToLower(prefix)]; known {
+			res.kind, pattern = kind, rest
+		}
+	}
+
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		expr, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return res, fmt.Errorf("%w: %s: %s", ErrInvalidFilter, pattern, err.Error())
+		}
+
+		res.match = expr.MatchString
+
+		return res, nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+		return res, fmt.Errorf("%w: %s is not a valid glob", ErrInvalidFilter, pattern)
+	}
+
+	res.match = func(key string) bool {
+		matched, _ := path.Match(pattern, key)
+
+		return matched
+	}
+
+	return res, nil
+}
+
+func parsePatterns(patterns []string) ([]keyPattern, error) {
+	res := make([]keyPattern, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		parsed, err := parsePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, parsed)
+	}
+
+	return res, nil
+}
+
+// parseAssignments parses `KEY=VALUE` pairs, allowEmpty accepts an empty value.
+func parseAssignments(assignments []string, allowEmpty bool) ([][2]string, error) {
+	res := make([][2]string, 0, len(assignments))
+
+	for _, assignment := range assignments {
+		key, value, found := strings.Cut(assignment, "=")
+		if !found || key == "" || (value == "" && !allowEmpty) {
+			return nil, fmt.Errorf("%w: %s must be in the form KEY=VALUE", ErrInvalidFilter, assignment)
+		}
+
+		res = append(res, [2]string{key, value})
+	}
+
+	return res, nil
+}
+
+// filters are the value expansions, key filters, renames and overrides of the output options.
+type filters struct {
+	expandKeys      []keyPattern
+	expandSeparator string
+	expandDepth     int
+	include         []keyPattern
+	exclude         []keyPattern
+	rename          map[string]string
+	set             [][2]string
+	unset           []string
+}
+
+func parseFilters(output options.Output) (*filters, error) {
+	expandKeys, err := parsePatterns(output.Expand)
+	if err != nil {
+		return nil, err
+	}
+
+	if output.ExpandDepth < 0 {
+		return nil, fmt.Errorf("%w: expand depth %d is negative", ErrInvalidFilter, output.ExpandDepth)
+	}
+
+	separator := output.ExpandSeparator
+	if separator == "" {
+		separator = defaultExpandSeparator
+	}
+
+	include, err := parsePatterns(output.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude, err := parsePatterns(output.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	renames, err := parseAssignments(output.Rename, false)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := parseAssignments(output.Set, true)
+	if err != nil {
+		return nil, err
+	}
+
+	rename := map[string]string{}
+	for _, pair := range renames {
+		rename[pair[0]] = pair[1]
+	}
+
+	return &filters{
+		expandKeys:      expandKeys,
+		expandSeparator: separator,
+		expandDepth:     output.ExpandDepth,
+		include:         include,
+		exclude:         exclude,
+		rename:          rename,
+		set:             set,
+		unset:           output.Unset,
+	}, nil
+}
+
+// ValidateFilters returns an error when a filter, rename or override of the output options cannot be parsed.
+func ValidateFilters(output options.Output) error {
+	_, err := parseFilters(output)
+
+	return err
+}
+
+// matches reports whether key of a source of kind matches any of patterns, and whether any pattern applies to kind.
+func matches(patterns []keyPattern, kind, key string) (bool, bool) {
+	applicable := false
+
+	for _, pattern := range patterns {
+		if pattern.kind != "" && pattern.kind != kind {
+			continue
+		}
+
+		applicable = true
+
+		if pattern.match(key) {
+			return true, true
+		}
+	}
+
+	return false, applicable
+}
+
+// keep reports whether a key passes the include and exclude filters.
+func (f *filters) keep(kind, key string) bool {
+	if included, applicable := matches(f.include, kind, key); applicable && !included {
+		return false
+	}
+
+	excluded, _ := matches(f.exclude, kind, key)
+
+	return !excluded
+}
+
+func (f *filters) apply(kind string, values EnvValues) (EnvValues, error) {
+	expanded, err := f.expand(kind, values)
+	if err != nil {
+		return nil, err
+	}
+
+	res := EnvValues{}
+
+	for key, value := range expanded {
+		if !f.keep(kind, key) {
+			continue
+		}
+
+		if renamed, found := f.rename[key]; found {
+			key = renamed
+		}
+
+		res[key] = value
+	}
+
+	for _, key := range f.unset {
+		delete(res, key)
+	}
+
+	for _, pair := range f.set {
+		delete(res, pair[0])
+	}
+
+	return res, nil
+}
+
+// applyEach applies the filters to every source of kind, dropping the sources left without keys.
+func (f *filters) applyEach(kind string, sources map[string]EnvValues) (map[string]EnvValues, error) {
+	res := make(map[string]EnvValues, len(sources))
+
+	for name, values := range sources {
+		filtered, err := f.apply(kind, values)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sourceName(kind, name), err)
+		}
+
+		if len(filtered) > 0 || len(values) == 0 {
+			res[name] = filtered
+		}
+	}
+
+	return res, nil
+}
+
+// filtered returns a copy of the result with the output filters applied, in order: `--expand`, `--include` and
+// `--exclude` on the expanded keys, `--rename`, `--unset`, then `--set` which replaces the key in every source with a
+// literal environment value. Sources left without keys are dropped.
+func (r *Result) filtered() (*Result, error) {
+	filters, err := parseFilters(r.output)
+	if err != nil {
+		return nil, err
+	}
+
+	res := *r
+
+	res.Environment, err = filters.apply(KindEnvironment, r.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	res.ConfigMaps, err = filters.applyEach(KindConfigMap, r.ConfigMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Secrets, err = filters.applyEach(KindSecret, r.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range filters.set {
+		res.Environment[pair[0]] = pair[1]
+	}
+
+	return &res, nil
+}
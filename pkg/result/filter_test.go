@@ -0,0 +1,228 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parsePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		kind    string
+		key     string
+		want    bool
+		wantErr bool
+	}{
+		{name: "glob", pattern: "DB_*", key: "DB_HOST", want: true},
+		{name: "glob mismatch", pattern: "DB_*", key: "API_DB_HOST"},
+		{name: "regex", pattern: "/^OTEL_EXPORTER_/", key: "OTEL_EXPORTER_OTLP_ENDPOINT", want: true},
+		{name: "regex mismatch", pattern: "/^OTEL_EXPORTER_/", key: "OTEL_SERVICE_NAME"},
+		{name: "secret prefix", pattern: "secret:DB_*", kind: KindSecret, key: "DB_PASSWORD", want: true},
+		{name: "configmap prefix", pattern: "ConfigMap:/HOST$/", kind: KindConfigMap, key: "DB_HOST", want: true},
+		{name: "env prefix", pattern: "env:*", kind: KindEnvironment, key: "A", want: true},
+		{name: "unknown prefix is part of the glob", pattern: "a:b", key: "a:b", want: true},
+		{name: "error on invalid regex", pattern: "/(/", wantErr: true},
+		{name: "error on invalid glob", pattern: "[", wantErr: true},
+		{name: "error on empty pattern", pattern: "secret:", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePattern() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Errorf("parsePattern() error = %v, want %v", err, ErrInvalidFilter)
+				}
+
+				return
+			}
+
+			if got.kind != tt.kind {
+				t.Errorf("parsePattern() kind = %q, want %q", got.kind, tt.kind)
+			}
+
+			if matched := got.match(tt.key); matched != tt.want {
+				t.Errorf("parsePattern() match(%q) = %v, want %v", tt.key, matched, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  options.Output
+		wantErr bool
+	}{
+		{name: "valid", output: options.Output{
+			Include: []string{"DB_*"},
+			Exclude: []string{"/^OTEL_/"},
+			Rename:  []string{"A=B"},
+			Set:     []string{"C=", "D=d=d"},
+			Unset:   []string{"E"},
+		}},
+		{name: "error on invalid include", output: options.Output{Include: []string{"["}}, wantErr: true},
+		{name: "error on invalid exclude", output: options.Output{Exclude: []string{"/(/"}}, wantErr: true},
+		{name: "error on rename without new name", output: options.Output{Rename: []string{"A="}}, wantErr: true},
+		{name: "error on rename without separator", output: options.Output{Rename: []string{"A"}}, wantErr: true},
+		{name: "error on set without key", output: options.Output{Set: []string{"=v"}}, wantErr: true},
+		{name: "error on invalid expand", output: options.Output{Expand: []string{"["}}, wantErr: true},
+		{name: "error on negative expand depth", output: options.Output{ExpandDepth: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateFilters(tt.output); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFilters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResult_filtered(t *testing.T) {
+	res := &Result{
+		Environment: EnvValues{"DB_NAME": "app", "OTEL_EXPORTER_OTLP_ENDPOINT": "http://otel", "PORT": "80"},
+		ConfigMaps:  map[string]EnvValues{"cm": {"DB_HOST": "db", "LOG_LEVEL": "info"}, "empty": {}},
+		Secrets:     map[string]EnvValues{"sec": {"DB_PASSWORD": "pass", "API_KEY": "key"}},
+	}
+	structured := &Result{
+		Environment: res.Environment,
+		ConfigMaps: map[string]EnvValues{
+			"cm":    res.ConfigMaps["cm"],
+			"empty": {},
+			"json":  {"credentials.json": `{"db": {"host": "cm", "port": 1}}`},
+		},
+		Secrets: map[string]EnvValues{
+			"sec":  res.Secrets["sec"],
+			"json": {"credentials.json": `{"db": {"host": "db", "port": 5432}}`, "plain.json": "not json"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		res    *Result
+		output options.Output
+		want   *Result
+	}{
+		{
+			name: "no filters",
+			want: res,
+		},
+		{
+			name:   "include",
+			output: options.Output{Include: []string{"DB_*"}},
+			want: &Result{
+				Environment: EnvValues{"DB_NAME": "app"},
+				ConfigMaps:  map[string]EnvValues{"cm": {"DB_HOST": "db"}, "empty": {}},
+				Secrets:     map[string]EnvValues{"sec": {"DB_PASSWORD": "pass"}},
+			},
+		},
+		{
+			name:   "exclude and drop sources left empty",
+			output: options.Output{Exclude: []string{"/^OTEL_EXPORTER_/", "configmap:*"}},
+			want: &Result{
+				Environment: EnvValues{"DB_NAME": "app", "PORT": "80"},
+				ConfigMaps:  map[string]EnvValues{"empty": {}},
+				Secrets:     map[string]EnvValues{"sec": {"DB_PASSWORD": "pass", "API_KEY": "key"}},
+			},
+		},
+		{
+			name:   "include per source",
+			output: options.Output{Include: []string{"secret:API_*", "env:PORT"}},
+			want: &Result{
+				Environment: EnvValues{"PORT": "80"},
+				ConfigMaps:  map[string]EnvValues{"cm": {"DB_HOST": "db", "LOG_LEVEL": "info"}, "empty": {}},
+				Secrets:     map[string]EnvValues{"sec": {"API_KEY": "key"}},
+			},
+		},
+		{
+			name: "rename, unset and set",
+			output: options.Output{
+				Include: []string{"DB_*", "PORT"},
+				Rename:  []string{"DB_HOST=DATABASE_HOST", "PORT=HTTP_PORT"},
+				Unset:   []string{"DB_NAME"},
+				Set:     []string{"DB_PASSWORD=local", "DEBUG=true"},
+			},
+			want: &Result{
+				Environment: EnvValues{"HTTP_PORT": "80", "DB_PASSWORD": "local", "DEBUG": "true"},
+				ConfigMaps:  map[string]EnvValues{"cm": {"DATABASE_HOST": "db"}, "empty": {}},
+				Secrets:     map[string]EnvValues{},
+			},
+		},
+		{
+			name: "expand secret values before filtering",
+			res:  structured,
+			output: options.Output{
+				Expand:  []string{"secret:*.json"},
+				Include: []string{"CREDENTIALS_DB_*", "DB_*"},
+				Rename:  []string{"CREDENTIALS_DB_HOST=DB_HOST"},
+			},
+			want: &Result{
+				Environment: EnvValues{"DB_NAME": "app"},
+				ConfigMaps:  map[string]EnvValues{"cm": {"DB_HOST": "db"}, "empty": {}},
+				Secrets:     map[string]EnvValues{"sec": {"DB_PASSWORD": "pass"}, "json": {"DB_HOST": "db", "CREDENTIALS_DB_PORT": "5432"}},
+			},
+		},
+		{
+			name:   "expand with separator and depth",
+			res:    structured,
+			output: options.Output{Expand: []string{"credentials.json"}, ExpandSeparator: "__", ExpandDepth: 1, Include: []string{"CREDENTIALS*"}},
+			want: &Result{
+				Environment: EnvValues{},
+				ConfigMaps:  map[string]EnvValues{"json": {"CREDENTIALS__DB": `{"host":"cm","port":1}`}, "empty": {}},
+				Secrets:     map[string]EnvValues{"json": {"CREDENTIALS__DB": `{"host":"db","port":5432}`}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := *res
+			if tt.res != nil {
+				input = *tt.res
+			}
+
+			input.output = tt.output
+
+			got, err := input.filtered()
+			if err != nil {
+				t.Fatalf("Result.filtered() error = %v", err)
+			}
+
+			want := *tt.want
+			want.output = tt.output
+
+			if !cmp.Equal(*got, want, cmp.AllowUnexported(Result{}, source{})) {
+				t.Errorf("Result.filtered() = %v", cmp.Diff(want, *got, cmp.AllowUnexported(Result{}, source{})))
+			}
+		})
+	}
+
+	t.Run("error on invalid filter", func(t *testing.T) {
+		input := *res
+		input.output = options.Output{Exclude: []string{"["}}
+
+		if _, err := input.filtered(); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("Result.filtered() error = %v, want %v", err, ErrInvalidFilter)
+		}
+	})
+
+	t.Run("error on expanded keys that collide", func(t *testing.T) {
+		input := *res
+		input.Secrets = map[string]EnvValues{"json": {"credentials.json": `{"db": {"host": "a"}, "DB_HOST": "b"}`}}
+		input.output = options.Output{Expand: []string{"credentials.json"}}
+
+		if _, err := input.filtered(); !errors.Is(err, parser.ErrDuplicateKey) {
+			t.Errorf("Result.filtered() error = %v, want %v", err, parser.ErrDuplicateKey)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package result
+
+import (
+	"sigs.k8s.io/yaml"
+)
+
+type helmEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type helmEnvFrom struct {
+	ConfigMaps map[string]EnvValues `json:"configMaps,omitempty"`
+	Secrets    map[string]EnvValues `json:"secrets,omitempty"`
+}
+
+type helmValues struct {
+	Env     []helmEnvVar `json:"env,omitempty"`
+	EnvFrom *helmEnvFrom `json:"envFrom,omitempty"`
+}
+
+// parseHelm renders a values.yaml fragment with an `env` list for environment values and
+// an `envFrom` map of configmaps and secrets keyed by name.
+func (r *Result) parseHelm() (string, error) {
+	res := helmValues{}
+
+	for _, key := range r.Environment.sortedKeys() {
+		res.Env = append(res.Env, helmEnvVar{Name: key, Value: r.Environment[key]})
+	}
+
+	if len(r.ConfigMaps) > 0 || len(r.Secrets) > 0 {
+		res.EnvFrom = &helmEnvFrom{ConfigMaps: r.ConfigMaps, Secrets: r.Secrets}
+	}
+
+	out, err := yaml.Marshal(res)
+	if err != nil {
+		return "", newManifestError(err)
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,49 @@
+package result
+
+import "testing"
+
+func TestResult_parseHelm(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Result
+		want string
+	}{
+		{
+			name: "values",
+			r: &Result{
+				Environment: EnvValues{"b": "2", "a": "1"},
+				ConfigMaps:  map[string]EnvValues{"app": {"cm": "val"}},
+				Secrets:     map[string]EnvValues{"db": {"sec": "val"}},
+			},
+			want: `env:
+- name: a
+  value: "1"
+- name: b
+  value: "2"
+envFrom:
+  configMaps:
+    app:
+      cm: val
+  secrets:
+    db:
+      sec: val
+`,
+		},
+		{
+			name: "environment only",
+			r:    &Result{Environment: EnvValues{"a": "1"}},
+			want: `env:
+- name: a
+  value: "1"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := tt.r.parseHelm(); got != tt.want {
+				t.Errorf("Result.parseHelm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
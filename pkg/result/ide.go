@@ -0,0 +1,318 @@
+package result
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+// ErrInvalidRunConfiguration is returned when an existing IDE run configuration cannot be updated.
+var ErrInvalidRunConfiguration = errors.New("invalid run configuration")
+
+func newRunConfigurationError(err error) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRunConfiguration, err.Error())
+}
+
+// updateFormats render the whole output file from its previous content, mapped to the file they update by default.
+func updateFormats() map[string]string {
+	return map[string]string{
+		"jetbrains": ".run/%s.run.xml",
+		"vscode":    ".vscode/launch.json",
+	}
+}
+
+// UpdatesFile reports whether a format rewrites the output file from its previous content instead of appending to it.
+func UpdatesFile(format string) bool {
+	_, found := updateFormats()[format]
+
+	return found
+}
+
+// DefaultFilename returns the file a format updates when no output file is given, or "" when it has none.
+func DefaultFilename(format, runConfiguration string) string {
+	name, found := updateFormats()[format]
+	if !found {
+		return ""
+	}
+
+	if strings.Contains(name, "%s") {
+		return fmt.Sprintf(name, runConfiguration)
+	}
+
+	return name
+}
+
+func (r *Result) runConfigurationName() string {
+	return r.manifestName(r.output.RunConfiguration)
+}
+
+// indentUnit guesses the indentation of a document from its first indented line.
+func indentUnit(data []byte, fallback string) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && trimmed != line && strings.TrimSpace(trimmed) != "" {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+
+	return fallback
+}
+
+// lineIndent returns the indentation of the line holding pos.
+func lineIndent(data []byte, pos int) string {
+	start := bytes.LastIndexByte(data[:pos], '\n') + 1
+	end := start
+
+	for end < pos && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+
+	return string(data[start:end])
+}
+
+func splice(data []byte, start, end int, value string) string {
+	return string(data[:start]) + value + string(data[end:])
+}
+
+// insertJSONC adds an item to the object or array between open and close, after last when it is not nil.
+func insertJSONC(data []byte, open, closing int, last *parser.JSONCSpan, unit string, item func(indent string) string) string {
+	if last == nil {
+		indent := lineIndent(data, open)
+		inner := strings.TrimRight(string(data[open+1:closing]), " \t\r\n")
+
+		return string(data[:open+1]) + inner + "\n" + indent + unit + item(indent+unit) + "\n" + indent + string(data[closing:])
+	}
+
+	indent := lineIndent(data, last.Start)
+
+	return string(data[:last.End]) + ",\n" + indent + item(indent) + string(data[last.End:])
+}
+
+func jsonValue(value interface{}, indent, unit string) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(indent, unit)
+	_ = enc.Encode(value)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func jsonObject(indent, unit string, members [][2]string) string {
+	lines := make([]string, 0, len(members))
+
+	for _, member := range members {
+		lines = append(lines, fmt.Sprintf("%s%s%s: %s", indent, unit, jsonValue(member[0], "", ""), member[1]))
+	}
+
+	return "{\n" + strings.Join(lines, ",\n") + "\n" + indent + "}"
+}
+
+func (r *Result) launchConfiguration(indent, unit string) string {
+	return jsonObject(indent, unit, [][2]string{
+		{"name", jsonValue(r.runConfigurationName(), "", "")},
+		{"type", `"go"`},
+		{"request", `"launch"`},
+		{"mode", `"auto"`},
+		{"program", `"${workspaceFolder}"`},
+		{"env", jsonValue(r.environment(), indent+unit, unit)},
+	})
+}
+
+func findMember(members []parser.JSONCMember, key string) *parser.JSONCMember {
+	for i := range members {
+		if members[i].Key == key {
+			return &members[i]
+		}
+	}
+
+	return nil
+}
+
+func lastMember(members []parser.JSONCMember) *parser.JSONCSpan {
+	if len(members) == 0 {
+		return nil
+	}
+
+	return &parser.JSONCSpan{Start: members[len(members)-1].KeyStart, End: members[len(members)-1].Value.End}
+}
+
+func lastElement(elements []parser.JSONCSpan) *parser.JSONCSpan {
+	if len(elements) == 0 {
+		return nil
+	}
+
+	return &elements[len(elements)-1]
+}
+
+// parseVSCode creates or updates the `env` of the named configuration in a VS Code launch.json,
+// anything else in the file, comments included, is kept as-is.
+func (r *Result) parseVSCode() (string, error) {
+	data := r.output.Original
+	unit := indentUnit(data, "\t")
+
+	root := parser.JSONCSkip(data, 0)
+	if root == len(data) {
+		configurations := "[\n" + unit + unit + r.launchConfiguration(unit+unit, unit) + "\n" + unit + "]"
+
+		return jsonObject("", unit, [][2]string{{"version", `"0.2.0"`}, {"configurations", configurations}}) + "\n", nil
+	}
+
+	members, closing, err := parser.JSONCMembers(data, root)
+	if err != nil {
+		return "", newRunConfigurationError(err)
+	}
+
+	configurations := findMember(members, "configurations")
+	if configurations == nil {
+		return insertJSONC(data, root, closing, lastMember(members), unit, func(indent string) string {
+			return `"configurations": [` + "\n" + indent + unit + r.launchConfiguration(indent+unit, unit) + "\n" + indent + "]"
+		}), nil
+	}
+
+	elements, closing, err := parser.JSONCElements(data, configurations.Value.Start)
+	if err != nil {
+		return "", newRunConfigurationError(err)
+	}
+
+	for _, element := range elements {
+		if data[element.Start] != '{' {
+			continue
+		}
+
+		config, configClosing, err := parser.JSONCMembers(data, element.Start)
+		if err != nil {
+			return "", newRunConfigurationError(err)
+		}
+
+		var name string
+		if member := findMember(config, "name"); member == nil ||
+			json.Unmarshal(data[member.Value.Start:member.Value.End], &name) != nil ||
+			name != r.runConfigurationName() {
+			continue
+		}
+
+		if env := findMember(config, "env"); env != nil {
+			return splice(data, env.Value.Start, env.Value.End,
+				jsonValue(r.environment(), lineIndent(data, env.KeyStart), unit)), nil
+		}
+
+		return insertJSONC(data, element.Start, configClosing, lastMember(config), unit, func(indent string) string {
+			return `"env": ` + jsonValue(r.environment(), indent, unit)
+		}), nil
+	}
+
+	return insertJSONC(data, configurations.Value.Start, closing, lastElement(elements), unit, func(indent string) string {
+		return r.launchConfiguration(indent, unit)
+	}), nil
+}
+
+func xmlAttr(value string) string {
+	var buf bytes.Buffer
+
+	_ = xml.EscapeText(&buf, []byte(value))
+
+	return buf.String()
+}
+
+func (r *Result) jetbrainsEnvs(indent, unit string) string {
+	env := r.environment()
+	if len(env) == 0 {
+		return "<envs />"
+	}
+
+	res := "<envs>\n"
+	for _, key := range EnvValues(env).sortedKeys() {
+		res += fmt.Sprintf("%s%s<env name=\"%s\" value=\"%s\" />\n", indent, unit, xmlAttr(key), xmlAttr(env[key]))
+	}
+
+	return res + indent + "</envs>"
+}
+
+// jetbrainsSpans finds the `<envs>` element of the run configuration, its start and end are -1 when there is none,
+// and the start of the closing `</configuration>` tag.
+func jetbrainsSpans(data []byte) (int, int, int, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	stack := []string{}
+	envsStart, envsEnd, configurationEnd := -1, -1, -1
+
+	for {
+		start := int(dec.InputOffset())
+
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return 0, 0, 0, newRunConfigurationError(err)
+		}
+
+		switch token := tok.(type) {
+		case xml.StartElement:
+			if token.Name.Local == "envs" && envsStart == -1 && len(stack) > 0 && stack[len(stack)-1] == "configuration" {
+				envsStart = start
+			}
+
+			stack = append(stack, token.Name.Local)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+
+			if token.Name.Local == "envs" && envsStart != -1 && envsEnd == -1 {
+				envsEnd = int(dec.InputOffset())
+			}
+
+			if token.Name.Local == "configuration" && configurationEnd == -1 {
+				configurationEnd = start
+			}
+		}
+	}
+
+	if configurationEnd == -1 {
+		return 0, 0, 0, ErrInvalidRunConfiguration
+	}
+
+	return envsStart, envsEnd, configurationEnd, nil
+}
+
+// parseJetBrains creates or updates the `<envs>` of a JetBrains `.run.xml` run configuration,
+// anything else in the file is kept as-is.
+func (r *Result) parseJetBrains() (string, error) {
+	data := r.output.Original
+	unit := indentUnit(data, "  ")
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		indent := unit + unit
+
+		return fmt.Sprintf(`<component name="ProjectRunConfigurationManager">
+%[1]s<configuration default="false" name="%[2]s" type="GoApplicationRunConfiguration" factoryName="Go Application">
+%[3]s<working_directory value="$PROJECT_DIR$" />
+%[3]s<kind value="DIRECTORY" />
+%[3]s<directory value="$PROJECT_DIR$" />
+%[3]s%[4]s
+%[3]s<method v="2" />
+%[1]s</configuration>
+</component>
+`, unit, xmlAttr(r.runConfigurationName()), indent, r.jetbrainsEnvs(indent, unit)), nil
+	}
+
+	envsStart, envsEnd, configurationEnd, err := jetbrainsSpans(data)
+	if err != nil {
+		return "", err
+	}
+
+	if envsStart != -1 {
+		return splice(data, envsStart, envsEnd, r.jetbrainsEnvs(lineIndent(data, envsStart), unit)), nil
+	}
+
+	indent := lineIndent(data, configurationEnd)
+
+	return splice(data, configurationEnd, configurationEnd, unit+r.jetbrainsEnvs(indent+unit, unit)+"\n"+indent), nil
+}
@@ -0,0 +1,262 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+)
+
+func TestDefaultFilename(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "vscode", format: "vscode", want: ".vscode/launch.json"},
+		{name: "jetbrains", format: "jetbrains", want: ".run/api.run.xml"},
+		{name: "no default", format: "dotenv", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultFilename(tt.format, "api"); got != tt.want {
+				t.Errorf("DefaultFilename() = %v, want %v", got, tt.want)
+			}
+
+			if got := UpdatesFile(tt.format); got != (tt.want != "") {
+				t.Errorf("UpdatesFile() = %v, want %v", got, tt.want != "")
+			}
+		})
+	}
+}
+
+func ideResult(original string) *Result {
+	return &Result{
+		output:      options.Output{RunConfiguration: "api", Original: []byte(original)},
+		Environment: EnvValues{"A": "1"},
+		Secrets:     map[string]EnvValues{"db": {"B": `"<2>"`}},
+	}
+}
+
+func TestResult_parseVSCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		want     string
+		wantErr  error
+	}{
+		{
+			name: "new file",
+			want: `{
+	"version": "0.2.0",
+	"configurations": [
+		{
+			"name": "api",
+			"type": "go",
+			"request": "launch",
+			"mode": "auto",
+			"program": "${workspaceFolder}",
+			"env": {
+				"A": "1",
+				"B": "\"<2>\""
+			}
+		}
+	]
+}
+`,
+		},
+		{
+			name: "replace env and keep comments",
+			original: `{
+  // my launch configurations
+  "version": "0.2.0",
+  "configurations": [
+    {"name": "other", "env": {"X": "y"}},
+    {
+      "name": "api", /* debug the api */
+      "env": {"OLD": "value"},
+      "args": ["-v"],
+    },
+  ]
+}
+`,
+			want: `{
+  // my launch configurations
+  "version": "0.2.0",
+  "configurations": [
+    {"name": "other", "env": {"X": "y"}},
+    {
+      "name": "api", /* debug the api */
+      "env": {
+        "A": "1",
+        "B": "\"<2>\""
+      },
+      "args": ["-v"],
+    },
+  ]
+}
+`,
+		},
+		{
+			name: "add env",
+			original: `{
+  "configurations": [
+    {
+      "name": "api",
+      "type": "node"
+    }
+  ]
+}
+`,
+			want: `{
+  "configurations": [
+    {
+      "name": "api",
+      "type": "node",
+      "env": {
+        "A": "1",
+        "B": "\"<2>\""
+      }
+    }
+  ]
+}
+`,
+		},
+		{
+			name: "add configuration",
+			original: `{
+  "version": "0.2.0",
+  "configurations": []
+}
+`,
+			want: `{
+  "version": "0.2.0",
+  "configurations": [
+    {
+      "name": "api",
+      "type": "go",
+      "request": "launch",
+      "mode": "auto",
+      "program": "${workspaceFolder}",
+      "env": {
+        "A": "1",
+        "B": "\"<2>\""
+      }
+    }
+  ]
+}
+`,
+		},
+		{
+			name:     "error on invalid file",
+			original: `{"configurations": [`,
+			wantErr:  ErrInvalidRunConfiguration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ideResult(tt.original).parseVSCode()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.parseVSCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Result.parseVSCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_parseJetBrains(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		want     string
+		wantErr  error
+	}{
+		{
+			name: "new file",
+			want: `<component name="ProjectRunConfigurationManager">
+  <configuration default="false" name="api" type="GoApplicationRunConfiguration" factoryName="Go Application">
+    <working_directory value="$PROJECT_DIR$" />
+    <kind value="DIRECTORY" />
+    <directory value="$PROJECT_DIR$" />
+    <envs>
+      <env name="A" value="1" />
+      <env name="B" value="&#34;&lt;2&gt;&#34;" />
+    </envs>
+    <method v="2" />
+  </configuration>
+</component>
+`,
+		},
+		{
+			name: "replace envs",
+			original: `<component name="ProjectRunConfigurationManager">
+	<configuration default="false" name="server" type="GoApplicationRunConfiguration">
+		<module name="app" />
+		<envs>
+			<env name="OLD" value="value" />
+		</envs>
+		<method v="2" />
+	</configuration>
+</component>
+`,
+			want: `<component name="ProjectRunConfigurationManager">
+	<configuration default="false" name="server" type="GoApplicationRunConfiguration">
+		<module name="app" />
+		<envs>
+			<env name="A" value="1" />
+			<env name="B" value="&#34;&lt;2&gt;&#34;" />
+		</envs>
+		<method v="2" />
+	</configuration>
+</component>
+`,
+		},
+		{
+			name: "add envs",
+			original: `<component name="ProjectRunConfigurationManager">
+  <configuration name="server" type="GoApplicationRunConfiguration">
+    <module name="app" />
+  </configuration>
+</component>
+`,
+			want: `<component name="ProjectRunConfigurationManager">
+  <configuration name="server" type="GoApplicationRunConfiguration">
+    <module name="app" />
+    <envs>
+      <env name="A" value="1" />
+      <env name="B" value="&#34;&lt;2&gt;&#34;" />
+    </envs>
+  </configuration>
+</component>
+`,
+		},
+		{
+			name:     "error without configuration",
+			original: `<component name="ProjectRunConfigurationManager" />`,
+			wantErr:  ErrInvalidRunConfiguration,
+		},
+		{
+			name:     "error on invalid file",
+			original: `<component>`,
+			wantErr:  ErrInvalidRunConfiguration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ideResult(tt.original).parseJetBrains()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.parseJetBrains() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Result.parseJetBrains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrMissingWorkload is returned when a workload is required but the result was not created from one.
+var ErrMissingWorkload = errors.New("missing workload")
+
+const defaultManifestName = "k8s-dotenv"
+
+func newManifestError(err error) error {
+	return fmt.Errorf("manifest error: %w", err)
+}
+
+// serverFields are removed from every manifest so the output can be applied to another namespace or cluster.
+func serverFields() [][]string {
+	return [][]string{
+		{"status"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "generation"},
+		{"metadata", "managedFields"},
+		{"metadata", "ownerReferences"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "selfLink"},
+		{"metadata", "uid"},
+		{"metadata", "annotations", "deployment.kubernetes.io/revision"},
+		{"metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration"},
+	}
+}
+
+func (r *Result) manifestName(name string) string {
+	if name != "" {
+		return name
+	}
+
+	if r.Workload != nil {
+		if accessor, err := meta.Accessor(r.Workload); err == nil {
+			return accessor.GetName()
+		}
+	}
+
+	return defaultManifestName
+}
+
+func manifest(obj runtime.Object, namespace string) (string, error) {
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return "", newManifestError(err)
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", newManifestError(err)
+	}
+
+	for _, field := range serverFields() {
+		unstructured.RemoveNestedField(content, field...)
+	}
+
+	if namespace == "" {
+		unstructured.RemoveNestedField(content, "metadata", "namespace")
+	} else {
+		_ = unstructured.SetNestedField(content, namespace, "metadata", "namespace")
+	}
+
+	if annotations, found, _ := unstructured.NestedMap(content, "metadata", "annotations"); found && len(annotations) == 0 {
+		unstructured.RemoveNestedField(content, "metadata", "annotations")
+	}
+
+	res, err := yaml.Marshal(content)
+	if err != nil {
+		return "", newManifestError(err)
+	}
+
+	return string(res), nil
+}
+
+// valueFromEnv returns the env entries set with valueFrom, such as a fieldRef or a secretKeyRef. Their values are
+// resolved when the pod starts so they stay on the container instead of moving to the generated ConfigMap.
+func valueFromEnv(env []corev1.EnvVar) []corev1.EnvVar {
+	var res []corev1.EnvVar
+
+	for _, entry := range env {
+		if entry.ValueFrom != nil {
+			res = append(res, entry)
+		}
+	}
+
+	return res
+}
+
+// valueFromKeys returns the names of the env entries of the workload set with valueFrom.
+func (r *Result) valueFromKeys() map[string]bool {
+	res := map[string]bool{}
+
+	if spec := PodSpec(r.Workload); spec != nil {
+		for _, container := range spec.Containers {
+			for _, entry := range valueFromEnv(container.Env) {
+				res[entry.Name] = true
+			}
+		}
+	}
+
+	return res
+}
+
+// patchedWorkload returns a copy of the workload where every container loads its environment from the given sources,
+// env entries set with valueFrom are kept.
+func (r *Result) patchedWorkload(configMap, secret string) (runtime.Object, error) {
+	if r.Workload == nil {
+		return nil, ErrMissingWorkload
+	}
+
+	workload := r.Workload.DeepCopyObject()
+	spec := PodSpec(workload)
+
+	if spec == nil {
+		return nil, ErrUnsupportedWorkload
+	}
+
+	envFrom := []corev1.EnvFromSource{}
+
+	if configMap != "" {
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: configMap}},
+		})
+	}
+
+	if secret != "" {
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret}},
+		})
+	}
+
+	for i := range spec.Containers {
+		spec.Containers[i].Env = valueFromEnv(spec.Containers[i].Env)
+		spec.Containers[i].EnvFrom = envFrom
+	}
+
+	return workload, nil
+}
+
+// parseK8s renders a ConfigMap for plain values and a Secret for secret values,
+// followed by the patched workload when requested. Env entries of the workload set with valueFrom are left out.
+func (r *Result) parseK8s() (string, error) {
+	plain := map[string]string{}
+	secret := map[string][]byte{}
+	valueFrom := r.valueFromKeys()
+
+	_ = r.each(func(kind, name, key, value string) error {
+		switch {
+		case kind == KindSecret:
+			secret[key] = []byte(value)
+		case kind == "" && valueFrom[key]:
+		default:
+			plain[key] = value
+		}
+
+		return nil
+	})
+
+	objects := []runtime.Object{}
+
+	var configMapName, secretName string
+
+	if len(plain) > 0 {
+		configMapName = r.manifestName(r.output.ConfigMapName)
+		objects = append(objects, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configMapName},
+			Data:       plain,
+		})
+	}
+
+	if len(secret) > 0 {
+		secretName = r.manifestName(r.output.SecretName)
+		objects = append(objects, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       secret,
+		})
+	}
+
+	if r.output.PatchWorkload {
+		workload, err := r.patchedWorkload(configMapName, secretName)
+		if err != nil {
+			return "", err
+		}
+
+		objects = append(objects, workload)
+	}
+
+	docs := make([]string, 0, len(objects))
+
+	for _, obj := range objects {
+		doc, err := manifest(obj, r.output.ManifestNamespace)
+		if err != nil {
+			return "", err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return strings.Join(docs, "---\n"), nil
+}
@@ -0,0 +1,182 @@
+package result
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestResult_manifestName(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        *Result
+		manifest string
+		want     string
+	}{
+		{name: "use configured name", r: &Result{}, manifest: "custom", want: "custom"},
+		{name: "use workload name", r: &Result{Workload: mock.Deployment("api", "test", nil, nil, nil)}, want: "api"},
+		{name: "use default name", r: &Result{}, want: defaultManifestName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.manifestName(tt.manifest); got != tt.want {
+				t.Errorf("Result.manifestName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_patchedWorkload(t *testing.T) {
+	workload := mock.Deployment("api", "test", map[string]string{"k": "v"}, []string{"cm"}, nil)
+	podName := corev1.EnvVar{
+		Name:      "POD_NAME",
+		ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+	}
+	valueFromWorkload := workload.DeepCopy()
+	valueFromWorkload.Spec.Template.Spec.Containers[0].Env = append(valueFromWorkload.Spec.Template.Spec.Containers[0].Env,
+		podName)
+
+	tests := []struct {
+		name    string
+		r       *Result
+		wantEnv []corev1.EnvVar
+		wantErr error
+	}{
+		{name: "error without workload", r: &Result{}, wantErr: ErrMissingWorkload},
+		{name: "error on unsupported workload", r: &Result{Workload: &corev1.Secret{}}, wantErr: ErrUnsupportedWorkload},
+		{name: "patch containers", r: &Result{Workload: workload}},
+		{name: "keep valueFrom entries", r: &Result{Workload: valueFromWorkload}, wantEnv: []corev1.EnvVar{podName}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.patchedWorkload("cm-name", "secret-name")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.patchedWorkload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			container := PodSpec(got).Containers[0]
+			if !reflect.DeepEqual(container.Env, tt.wantEnv) || len(container.EnvFrom) != 2 ||
+				container.EnvFrom[0].ConfigMapRef.Name != "cm-name" || container.EnvFrom[1].SecretRef.Name != "secret-name" {
+				t.Errorf("Result.patchedWorkload() container = %v", container)
+			}
+
+			if len(workload.Spec.Template.Spec.Containers[0].Env) != 1 {
+				t.Errorf("Result.patchedWorkload() modified the original workload")
+			}
+		})
+	}
+}
+
+func TestResult_parseK8s(t *testing.T) {
+	workload := mock.Deployment("api", "test", nil, nil, nil)
+	workload.ResourceVersion = "1"
+	workload.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{
+		Name:      "POD_NAME",
+		ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+	}}
+
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "configmap and secret",
+			r: &Result{
+				Environment: EnvValues{"env": "val"},
+				ConfigMaps:  map[string]EnvValues{"test": {"cm": "val"}},
+				Secrets:     map[string]EnvValues{"test": {"sec": "val"}},
+			},
+			want: `apiVersion: v1
+data:
+  cm: val
+  env: val
+kind: ConfigMap
+metadata:
+  name: k8s-dotenv
+---
+apiVersion: v1
+data:
+  sec: dmFs
+kind: Secret
+metadata:
+  name: k8s-dotenv
+type: Opaque
+`,
+		},
+		{
+			name: "patched workload",
+			r: &Result{
+				output: options.Output{
+					ConfigMapName:     "cm",
+					ManifestNamespace: "dev",
+					PatchWorkload:     true,
+				},
+				Environment: EnvValues{"env": "val", "POD_NAME": ""},
+				Workload:    workload,
+			},
+			want: `apiVersion: v1
+data:
+  env: val
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: dev
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: dev
+spec:
+  selector: null
+  strategy: {}
+  template:
+    metadata:
+      creationTimestamp: null
+    spec:
+      containers:
+      - env:
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.name
+        envFrom:
+        - configMapRef:
+            name: cm
+        name: ""
+        resources: {}
+`,
+		},
+		{
+			name:    "error on missing workload",
+			r:       &Result{output: options.Output{PatchWorkload: true}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseK8s()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Result.parseK8s() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Result.parseK8s() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrMissingFileWriter is returned when a format writes companion files but no file writer has been set.
+var ErrMissingFileWriter = errors.New("missing file writer")
+
+// WritesCompanionFiles reports whether the output writes files next to the output file that it refers to, the
+// kustomize format and `SecretTypes` do.
+func WritesCompanionFiles(output options.Output) bool {
+	return output.SecretTypes || output.Format == "kustomize"
+}
+
+type kustomizeGenerator struct {
+	Name  string   `json:"name"`
+	Envs  []string `json:"envs,omitempty"`
+	Files []string `json:"files,omitempty"`
+}
+
+type kustomization struct {
+	ConfigMapGenerator []kustomizeGenerator `json:"configMapGenerator,omitempty"`
+	SecretGenerator    []kustomizeGenerator `json:"secretGenerator,omitempty"`
+}
+
+func (r *Result) writeFile(name, content string, secret bool) error {
+	if r.output.WriteFile == nil {
+		return fmt.Errorf("%w: companion files are only written next to an output file, use --outfile", ErrMissingFileWriter)
+	}
+
+	perm := FilePerm
+	if secret {
+		perm = SecretFilePerm
+	}
+
+	if err := r.output.WriteFile(name, []byte(content), perm); err != nil {
+		return newWriteError(err)
+	}
+
+	return nil
+}
+
+// kustomizeGenerator writes the companion files for a source, multiline values get a file of their own
+// since kustomize env files cannot hold them.
+func (r *Result) kustomizeGenerator(kind, name string, values EnvValues) (kustomizeGenerator, error) {
+	prefix := "environment"
+	if kind != "" {
+		prefix = fmt.Sprintf("%s-%s", strings.ToLower(kind), name)
+	}
+
+	generator := kustomizeGenerator{Name: name}
+
+	var env string
+
+	for _, key := range values.sortedKeys() {
+		if !strings.ContainsAny(values[key], "\r\n") {
+			env += parser.Kustomize(key, values[key])
+
+			continue
+		}
+
+		file := fmt.Sprintf("%s-%s", prefix, key)
+		if err := r.writeFile(file, values[key], kind == KindSecret); err != nil {
+			return generator, err
+		}
+
+		generator.Files = append(generator.Files, fmt.Sprintf("%s=%s", key, file))
+	}
+
+	if env != "" {
+		file := prefix + ".env"
+		if err := r.writeFile(file, env, kind == KindSecret); err != nil {
+			return generator, err
+		}
+
+		generator.Envs = []string{file}
+	}
+
+	return generator, nil
+}
+
+// parseKustomize renders `configMapGenerator` and `secretGenerator` blocks and writes a companion file per source.
+func (r *Result) parseKustomize() (string, error) {
+	res := kustomization{}
+
+	if len(r.Environment) > 0 {
+		generator, err := r.kustomizeGenerator("", r.manifestName(r.output.ConfigMapName), r.Environment)
+		if err != nil {
+			return "", err
+		}
+
+		res.ConfigMapGenerator = append(res.ConfigMapGenerator, generator)
+	}
+
+	for _, name := range sortedNames(r.ConfigMaps) {
+		generator, err := r.kustomizeGenerator(KindConfigMap, name, r.ConfigMaps[name])
+		if err != nil {
+			return "", err
+		}
+
+		res.ConfigMapGenerator = append(res.ConfigMapGenerator, generator)
+	}
+
+	for _, name := range sortedNames(r.Secrets) {
+		generator, err := r.kustomizeGenerator(KindSecret, name, r.Secrets[name])
+		if err != nil {
+			return "", err
+		}
+
+		res.SecretGenerator = append(res.SecretGenerator, generator)
+	}
+
+	out, err := yaml.Marshal(res)
+	if err != nil {
+		return "", newManifestError(err)
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,146 @@
+package result
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+)
+
+type fakeFiles map[string]string
+
+func (files fakeFiles) write(name string, data []byte, perm os.FileMode) error {
+	files[name] = string(data)
+
+	return nil
+}
+
+func TestWritesCompanionFiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		output options.Output
+		want   bool
+	}{
+		{name: "dotenv", output: options.Output{Format: "dotenv"}},
+		{name: "kustomize", output: options.Output{Format: "kustomize"}, want: true},
+		{name: "secret types", output: options.Output{Format: "dotenv", SecretTypes: true}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WritesCompanionFiles(tt.output); got != tt.want {
+				t.Errorf("WritesCompanionFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_writeFile(t *testing.T) {
+	var gotPerm os.FileMode
+
+	tests := []struct {
+		name     string
+		r        *Result
+		secret   bool
+		wantPerm os.FileMode
+		wantErr  error
+	}{
+		{name: "error without file writer", r: &Result{}, wantErr: ErrMissingFileWriter},
+		{
+			name: "return file writer errors",
+			r: &Result{output: options.Output{WriteFile: func(string, []byte, os.FileMode) error {
+				return mock.AnError
+			}}},
+			wantErr: mock.AnError,
+		},
+		{
+			name: "restrict secret files",
+			r: &Result{output: options.Output{WriteFile: func(_ string, _ []byte, perm os.FileMode) error {
+				gotPerm = perm
+
+				return nil
+			}}},
+			secret:   true,
+			wantPerm: SecretFilePerm,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.r.writeFile("name", "content", tt.secret)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.writeFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && gotPerm != tt.wantPerm {
+				t.Errorf("Result.writeFile() perm = %v, want %v", gotPerm, tt.wantPerm)
+			}
+		})
+	}
+}
+
+func TestResult_parseKustomize(t *testing.T) {
+	files := fakeFiles{}
+
+	tests := []struct {
+		name      string
+		r         *Result
+		want      string
+		wantFiles fakeFiles
+		wantErr   bool
+	}{
+		{
+			name: "generators",
+			r: &Result{
+				output:      options.Output{WriteFile: files.write},
+				Environment: EnvValues{"env": "val"},
+				ConfigMaps:  map[string]EnvValues{"app": {"cm": "val"}},
+				Secrets:     map[string]EnvValues{"db": {"cert": "a\nb", "pass": "val"}},
+			},
+			want: `configMapGenerator:
+- envs:
+  - environment.env
+  name: k8s-dotenv
+- envs:
+  - configmap-app.env
+  name: app
+secretGenerator:
+- envs:
+  - secret-db.env
+  files:
+  - cert=secret-db-cert
+  name: db
+`,
+			wantFiles: fakeFiles{
+				"environment.env":   "env=val\n",
+				"configmap-app.env": "cm=val\n",
+				"secret-db.env":     "pass=val\n",
+				"secret-db-cert":    "a\nb",
+			},
+		},
+		{
+			name:    "error without file writer",
+			r:       &Result{Secrets: map[string]EnvValues{"db": {"pass": "val"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseKustomize()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Result.parseKustomize() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Result.parseKustomize() = %v, want %v", got, tt.want)
+			}
+			if tt.wantFiles != nil && !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("Result.parseKustomize() files = %v, want %v", files, tt.wantFiles)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package result
+
+import "github.com/eiladin/k8s-dotenv/pkg/parser"
+
+// parseMake renders a Makefile fragment that can be used with `include`.
+func (r *Result) parseMake() (string, error) {
+	var res, current string
+
+	err := r.each(func(kind, name, key, value string) error {
+		if source := sourceName(kind, name); kind != "" && source != current {
+			res += configHeader(kind, name)
+			current = source
+		}
+
+		line, err := parser.Make(r.shouldExport, key, value)
+		if err != nil {
+			return err
+		}
+
+		res += line
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
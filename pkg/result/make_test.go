@@ -0,0 +1,51 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+func TestResult_parseMake(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr error
+	}{
+		{
+			name: "makefile fragment",
+			r: &Result{
+				shouldExport: true,
+				Environment:  EnvValues{"env": "$val"},
+				ConfigMaps:   map[string]EnvValues{"test": {"cm": "val"}},
+				Secrets:      map[string]EnvValues{"test": {"sec": "val"}},
+			},
+			want: `export env := $$val
+# CONFIGMAP - test
+export cm := val
+# SECRET - test
+export sec := val
+`,
+		},
+		{
+			name:    "error on invalid key",
+			r:       &Result{Environment: EnvValues{"a=b": "val"}},
+			wantErr: parser.ErrInvalidKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseMake()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.parseMake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Result.parseMake() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package result
+
+import (
+	"encoding/json"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+const defaultKeySeparator = "__"
+
+// parseNestedJSON renders hierarchical JSON, such as ASP.NET's appsettings.json, by splitting keys on separators.
+//
+// Keys defined more than once keep their last value, matching what sourcing a .env file would do.
+func (r *Result) parseNestedJSON() (string, error) {
+	separators := r.output.KeySeparators
+	if len(separators) == 0 {
+		separators = []string{defaultKeySeparator}
+	}
+
+	nested, err := parser.Unflatten(r.environment(), separators)
+	if err != nil {
+		//nolint
+		return "", err
+	}
+
+	res, err := json.MarshalIndent(nested, "", "  ")
+	if err != nil {
+		return "", newWriteError(err)
+	}
+
+	return string(res) + "\n", nil
+}
@@ -0,0 +1,62 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+)
+
+func TestResult_parseNestedJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default separator",
+			r: &Result{
+				Environment: EnvValues{"Logging__LogLevel__Default": "Information"},
+				Secrets:     map[string]EnvValues{"db": {"ConnectionStrings__Default": "Server=db"}},
+			},
+			want: `{
+  "ConnectionStrings": {
+    "Default": "Server=db"
+  },
+  "Logging": {
+    "LogLevel": {
+      "Default": "Information"
+    }
+  }
+}
+`,
+		},
+		{
+			name: "custom separator",
+			r: &Result{
+				output:      options.Output{KeySeparators: []string{"."}},
+				Environment: EnvValues{"a.b": "v"},
+			},
+			want: "{\n  \"a\": {\n    \"b\": \"v\"\n  }\n}\n",
+		},
+		{
+			name:    "return conflicts",
+			r:       &Result{Environment: EnvValues{"a": "v", "a__b": "v"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseNestedJSON()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Result.parseNestedJSON() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Result.parseNestedJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package result
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Redaction styles.
+const (
+	// RedactMask replaces the value with a fixed mask.
+	RedactMask = "mask"
+	// RedactLength shows the length of the value.
+	RedactLength = "length"
+	// RedactHash shows a prefix of the SHA-256 hash of the value, so values can be compared without showing them.
+	RedactHash = "hash"
+	// RedactPrefix shows the first characters of the value.
+	RedactPrefix = "prefix"
+)
+
+// ErrUnsupportedRedaction is returned when the redaction style is unknown.
+var ErrUnsupportedRedaction = errors.New("unsupported redaction style")
+
+const (
+	redactedMask    = "********"
+	hashPrefixBytes = 4
+)
+
+// RedactStyles returns the supported redaction styles.
+func RedactStyles() []string {
+	return []string{RedactMask, RedactLength, RedactHash, RedactPrefix}
+}
+
+// redactValue hides value according to style, prefix shows the first chars characters and masks values that are
+// not longer than that.
+func redactValue(value, style string, chars int) (string, error) {
+	switch style {
+	case "", RedactMask:
+		return redactedMask, nil
+	case RedactLength:
+		return fmt.Sprintf("%s(%d)", redactedMask, len([]rune(value))), nil
+	case RedactHash:
+		sum := sha256.Sum256([]byte(value))
+
+		return fmt.Sprintf("%s(sha256:%s)", redactedMask, hex.EncodeToString(sum[:hashPrefixBytes])), nil
+	case RedactPrefix:
+		runes := []rune(value)
+		if chars <= 0 || len(runes) <= chars {
+			return redactedMask, nil
+		}
+
+		return string(runes[:chars]) + strings.Repeat("*", len(redactedMask)), nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedRedaction, style)
+}
+
+// redacted returns a copy of the result with every secret value redacted, configmap and environment values are kept.
+func (r *Result) redacted() (*Result, error) {
+	res := *r
+	res.output.Redact = false
+	res.redactedValues = r.ContainsSecrets()
+	res.Secrets = make(map[string]EnvValues, len(r.Secrets))
+
+	for name, values := range r.Secrets {
+		res.Secrets[name] = make(EnvValues, len(values))
+
+		for key, value := range values {
+			redacted, err := redactValue(value, r.output.RedactStyle, r.output.RedactChars)
+			if err != nil {
+				return nil, err
+			}
+
+			res.Secrets[name][key] = redacted
+		}
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,69 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+)
+
+func Test_redactValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		style   string
+		chars   int
+		want    string
+		wantErr error
+	}{
+		{name: "default mask", value: "secret", want: "********"},
+		{name: "length", value: "sécret", style: RedactLength, want: "********(6)"},
+		{name: "hash", value: "secret", style: RedactHash, want: "********(sha256:2bb80d53)"},
+		{name: "prefix", value: "secret", style: RedactPrefix, chars: 2, want: "se********"},
+		{name: "prefix of short value", value: "ab", style: RedactPrefix, chars: 2, want: "********"},
+		{name: "error on unknown style", value: "secret", style: "rot13", wantErr: ErrUnsupportedRedaction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := redactValue(tt.value, tt.style, tt.chars)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("redactValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("redactValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_render_redact(t *testing.T) {
+	r := &Result{
+		output:      options.Output{Redact: true},
+		Environment: EnvValues{"env": "val"},
+		ConfigMaps:  map[string]EnvValues{"test": {"cm": "val"}},
+		Secrets:     map[string]EnvValues{"test": {"sec": "val"}},
+	}
+
+	want := `# k8s-dotenv: redacted
+env="val"
+##### CONFIGMAP - test #####
+cm="val"
+##### SECRET - test #####
+sec="********"
+`
+
+	if got, err := r.render(); err != nil || got != want {
+		t.Errorf("Result.render() = %v, %v, want %v", got, err, want)
+	}
+
+	if r.Secrets["test"]["sec"] != "val" {
+		t.Errorf("Result.render() changed the result secrets")
+	}
+
+	r.output.RedactStyle = "rot13"
+	if _, err := r.render(); !errors.Is(err, ErrUnsupportedRedaction) {
+		t.Errorf("Result.render() error = %v, wantErr %v", err, ErrUnsupportedRedaction)
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"sort"
+	"strings"
 
+	"github.com/eiladin/k8s-dotenv/pkg/options"
 	"github.com/eiladin/k8s-dotenv/pkg/parser"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -19,6 +23,23 @@ var ErrMissingResource = errors.New("resource not found")
 // ErrMissingWriter is returned when no writer has been set.
 var ErrMissingWriter = errors.New("missing writer")
 
+// ErrUnsupportedFormat is returned when the output format is unknown.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+const (
+	// FilePerm is the permission of output files without secret values.
+	FilePerm os.FileMode = 0o644
+	// SecretFilePerm is the permission of output files with secret values, they are only readable by their owner.
+	SecretFilePerm os.FileMode = 0o600
+)
+
+const (
+	// KindConfigMap is the kind of values loaded from a configmap.
+	KindConfigMap = "CONFIGMAP"
+	// KindSecret is the kind of values loaded from a secret.
+	KindSecret = "SECRET"
+)
+
 func newWriteError(err error) error {
 	return fmt.Errorf("write error: %w", err)
 }
@@ -27,9 +48,17 @@ func newWriteError(err error) error {
 type Result struct {
 	Error        error
 	shouldExport bool
+	output       options.Output
 	Environment  EnvValues
 	Secrets      map[string]EnvValues
 	ConfigMaps   map[string]EnvValues
+	Workload     runtime.Object
+	container    string
+	files        []secretFile
+	// envFrom lists the ConfigMaps and Secrets in the order of the envFrom entries of the containers.
+	envFrom []source
+	// redactedValues is set on the copy rendered by `redacted` when it hid any secret value.
+	redactedValues bool
 }
 
 func newResult() *Result {
@@ -40,6 +69,12 @@ func newResult() *Result {
 	}
 }
 
+// source is a ConfigMap or Secret of a result, or its environment when kind is empty.
+type source struct {
+	kind string
+	name string
+}
+
 // EnvValues stores data returned from Environment, ConfigMaps or Secrets.
 type EnvValues map[string]string
 
@@ -55,6 +90,18 @@ func (env EnvValues) sortedKeys() []string {
 	return keys
 }
 
+func sortedNames(values map[string]EnvValues) []string {
+	names := make([]string, 0, len(values))
+
+	for k := range values {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
 func configMapData(client kubernetes.Interface, namespace, resource string) (map[string]string, error) {
 	resp, err := client.
 		CoreV1().
@@ -68,14 +115,25 @@ func configMapData(client kubernetes.Interface, namespace, resource string) (map
 	return resp.Data, nil
 }
 
-func secretData(client kubernetes.Interface, namespace, resource string) (map[string]string, error) {
+func secretData(
+	client kubernetes.Interface,
+	output options.Output,
+	namespace, resource string,
+) (map[string]string, []secretFile, error) {
 	resp, err := client.
 		CoreV1().
 		Secrets(namespace).
 		Get(context.TODO(), resource, metav1.GetOptions{})
 
 	if err != nil {
-		return nil, ErrMissingResource
+		return nil, nil, ErrMissingResource
+	}
+
+	if output.SecretTypes {
+		values, files, err := typedSecretData(resp, output)
+		if err != nil || values != nil {
+			return values, files, err
+		}
 	}
 
 	res := make(map[string]string)
@@ -84,7 +142,7 @@ func secretData(client kubernetes.Interface, namespace, resource string) (map[st
 		res[k] = string(v)
 	}
 
-	return res, nil
+	return res, nil, nil
 }
 
 // NewFromError creates a Result given an error.
@@ -98,12 +156,13 @@ func NewFromError(err error) *Result {
 // NewFromContainers creates a Result given []Container.
 func NewFromContainers(
 	client kubernetes.Interface,
-	namespace string,
-	shouldExport bool,
+	opt *options.Client,
 	containers []corev1.Container,
 ) *Result {
+	namespace := opt.Namespace
 	res := newResult()
-	res.shouldExport = shouldExport
+	res.shouldExport = opt.ShouldExport
+	res.output = opt.Output
 
 	for _, cont := range containers {
 		for _, env := range cont.Env {
@@ -120,49 +179,312 @@ func NewFromContainers(
 				}
 
 				res.ConfigMaps[name] = configMap
+				res.envFrom = append(res.envFrom, source{kind: KindConfigMap, name: name})
 			}
 
 			if envFrom.SecretRef != nil {
 				name := envFrom.SecretRef.Name
-				sec, err := secretData(client, namespace, name)
+				sec, files, err := secretData(client, opt.Output, namespace, name)
 
 				if err != nil {
 					return NewFromError(err)
 				}
 
 				res.Secrets[name] = sec
+				res.envFrom = append(res.envFrom, source{kind: KindSecret, name: name})
+				res.files = append(res.files, files...)
 			}
 		}
 	}
 
-	return res
+	if opt.Output.SecretTypes && opt.Output.DockerConfig {
+		if err := checkDockerConfig(res.Secrets); err != nil {
+			return NewFromError(err)
+		}
+	}
+
+	filtered, err := res.filtered()
+	if err != nil {
+		return NewFromError(err)
+	}
+
+	return filtered
 }
 
-func (r *Result) parse() string {
+// Formats returns the names of the supported output formats.
+func Formats() []string {
+	names := []string{}
+	for name := range formats() {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func formats() map[string]func(r *Result) (string, error) {
+	return map[string]func(r *Result) (string, error){
+		"appsettings":    (*Result).parseNestedJSON,
+		"docker":         (*Result).parseDocker,
+		"dotenv":         (*Result).parse,
+		"github-actions": (*Result).parseGitHubActions,
+		"gitlab":         (*Result).parseGitLab,
+		"helm":           (*Result).parseHelm,
+		"ini":            (*Result).parseINI,
+		"jetbrains":      (*Result).parseJetBrains,
+		"k8s":            (*Result).parseK8s,
+		"kustomize":      (*Result).parseKustomize,
+		"make":           (*Result).parseMake,
+		"nested-json":    (*Result).parseNestedJSON,
+		"properties":     (*Result).parseProperties,
+		"script":         (*Result).parseScript,
+		"systemd":        (*Result).parseSystemd,
+		"template":       (*Result).parseTemplate,
+		"tfvars":         (*Result).parseTFVars,
+		"toml":           (*Result).parseTOML,
+		"vscode":         (*Result).parseVSCode,
+	}
+}
+
+// lines renders the result one key at a time, with a header line before each configmap and secret.
+func (r *Result) lines(header func(kind, name string) string, line func(key, value string) string) string {
 	var res string
 
-	envKeys := r.Environment.sortedKeys()
-	for _, k := range envKeys {
-		res += parser.ParseStr(r.shouldExport, k, r.Environment[k])
+	for _, k := range r.Environment.sortedKeys() {
+		res += line(k, r.Environment[k])
 	}
 
-	for k, v := range r.ConfigMaps {
-		res += fmt.Sprintf("##### CONFIGMAP - %s #####\n", k)
-		for _, key := range v.sortedKeys() {
-			res += parser.ParseStr(r.shouldExport, key, v[key])
+	for _, name := range sortedNames(r.ConfigMaps) {
+		res += header(KindConfigMap, name)
+		for _, key := range r.ConfigMaps[name].sortedKeys() {
+			res += line(key, r.ConfigMaps[name][key])
 		}
 	}
 
-	for k, v := range r.Secrets {
-		res += fmt.Sprintf("##### SECRET - %s #####\n", k)
-		for _, key := range v.sortedKeys() {
-			res += parser.ParseStr(r.shouldExport, key, v[key])
+	for _, name := range sortedNames(r.Secrets) {
+		res += header(KindSecret, name)
+		for _, key := range r.Secrets[name].sortedKeys() {
+			res += line(key, r.Secrets[name][key])
 		}
 	}
 
 	return res
 }
 
+// each calls fn for every key in the same order as `lines`, kind and name are empty for environment values.
+func (r *Result) each(fn func(kind, name, key, value string) error) error {
+	for _, k := range r.Environment.sortedKeys() {
+		if err := fn("", "", k, r.Environment[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedNames(r.ConfigMaps) {
+		for _, key := range r.ConfigMaps[name].sortedKeys() {
+			if err := fn(KindConfigMap, name, key, r.ConfigMaps[name][key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range sortedNames(r.Secrets) {
+		for _, key := range r.Secrets[name].sortedKeys() {
+			if err := fn(KindSecret, name, key, r.Secrets[name][key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// precedence returns the sources of the result from the lowest to the highest precedence, the way Kubernetes
+// resolves a key found in several of them: envFrom sources in the order of the pod spec, a later entry winning, then
+// the env entries which override every envFrom source. Sources that were not loaded from a pod spec, such as the
+// sections of a .env file, come first, ConfigMaps then Secrets sorted by name.
+func (r *Result) precedence() []source {
+	last := map[source]int{}
+	for i, ref := range r.envFrom {
+		last[ref] = i
+	}
+
+	res := []source{}
+
+	for _, name := range sortedNames(r.ConfigMaps) {
+		if _, found := last[source{kind: KindConfigMap, name: name}]; !found {
+			res = append(res, source{kind: KindConfigMap, name: name})
+		}
+	}
+
+	for _, name := range sortedNames(r.Secrets) {
+		if _, found := last[source{kind: KindSecret, name: name}]; !found {
+			res = append(res, source{kind: KindSecret, name: name})
+		}
+	}
+
+	for i, ref := range r.envFrom {
+		if last[ref] == i {
+			res = append(res, ref)
+		}
+	}
+
+	return append(res, source{})
+}
+
+// values returns the values of a source of the result.
+func (r *Result) values(ref source) EnvValues {
+	switch ref.kind {
+	case KindConfigMap:
+		return r.ConfigMaps[ref.name]
+	case KindSecret:
+		return r.Secrets[ref.name]
+	}
+
+	return r.Environment
+}
+
+// eachLast calls fn like `each`, skipping values overridden by a source of higher precedence, see `precedence`.
+// Keys are compared after applying key.
+func (r *Result) eachLast(key func(string) string, fn func(kind, name, key, value string) error) error {
+	last := map[string]string{}
+
+	for _, ref := range r.precedence() {
+		for k := range r.values(ref) {
+			last[key(k)] = sourceName(ref.kind, ref.name)
+		}
+	}
+
+	return r.each(func(kind, name, k, value string) error {
+		if last[key(k)] != sourceName(kind, name) {
+			return nil
+		}
+
+		return fn(kind, name, k, value)
+	})
+}
+
+// environment returns every key with the value a container of the workload sees, see `precedence`.
+func (r *Result) environment() map[string]string {
+	res := map[string]string{}
+
+	for _, ref := range r.precedence() {
+		for key, value := range r.values(ref) {
+			res[key] = value
+		}
+	}
+
+	return res
+}
+
+// Values returns the environment a process gets when the result is loaded, env entries override envFrom sources
+// and later envFrom sources override earlier ones, like Kubernetes does.
+func (r *Result) Values() (map[string]string, error) {
+	if r.Error != nil {
+		return nil, r.Error
+	}
+
+	return r.environment(), nil
+}
+
+func sourceName(kind, name string) string {
+	if kind == "" {
+		return "environment"
+	}
+
+	return fmt.Sprintf("%s %s", strings.ToLower(kind), name)
+}
+
+func sectionHeader(kind, name string) string {
+	return fmt.Sprintf("##### %s - %s #####\n", kind, name)
+}
+
+// TransformsComment starts the dotenv comment listing how the values differ from the cluster, see `transforms`.
+const TransformsComment = "# k8s-dotenv:"
+
+// Transforms of the values written by k8s-dotenv, a file holding any of them cannot be applied back to the cluster.
+const (
+	TransformRedacted    = "redacted"
+	TransformFiltered    = "filtered"
+	TransformExpanded    = "expanded"
+	TransformSecretTypes = "secret-types"
+)
+
+// transforms returns how the rendered values differ from the ones of the cluster, in a stable order.
+func (r *Result) transforms() []string {
+	res := []string{}
+
+	if r.redactedValues {
+		res = append(res, TransformRedacted)
+	}
+
+	output := r.output
+	if len(output.Include)+len(output.Exclude)+len(output.Rename)+len(output.Set)+len(output.Unset) > 0 {
+		res = append(res, TransformFiltered)
+	}
+
+	if len(output.Expand) > 0 {
+		res = append(res, TransformExpanded)
+	}
+
+	if output.SecretTypes {
+		res = append(res, TransformSecretTypes)
+	}
+
+	return res
+}
+
+func (r *Result) parse() (string, error) {
+	res := ""
+	if transforms := r.transforms(); len(transforms) > 0 {
+		res = fmt.Sprintf("%s %s\n", TransformsComment, strings.Join(transforms, ", "))
+	}
+
+	return res + r.lines(sectionHeader, func(key, value string) string {
+		return parser.ParseStr(r.shouldExport, key, value)
+	}), nil
+}
+
+func (r *Result) render() (string, error) {
+	if r.output.Redact {
+		redacted, err := r.redacted()
+		if err != nil {
+			return "", err
+		}
+
+		return redacted.render()
+	}
+
+	format := r.output.Format
+	if format == "" {
+		format = "dotenv"
+	}
+
+	render, found := formats()[format]
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	return render(r)
+}
+
+// SecretWriter is implemented by writers that protect output containing secret values,
+// `Write` tells them whether the result has any before writing.
+type SecretWriter interface {
+	SetContainsSecrets(secrets bool)
+}
+
+// ContainsSecrets reports whether any value was loaded from a secret.
+func (r *Result) ContainsSecrets() bool {
+	for _, values := range r.Secrets {
+		if len(values) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *Result) Write(writer io.Writer) error {
 	if r.Error != nil {
 		return r.Error
@@ -172,7 +494,23 @@ func (r *Result) Write(writer io.Writer) error {
 		return ErrMissingWriter
 	}
 
-	output := r.parse()
+	output, err := r.render()
+	if err != nil {
+		return err
+	}
+
+	// Redacted output is only meant to be looked at, the files of typed Secrets would hold the values it hides.
+	if !r.output.Redact {
+		for _, file := range r.files {
+			if err := r.writeFile(file.name, string(file.data), true); err != nil {
+				return err
+			}
+		}
+	}
+
+	if secretWriter, ok := writer.(SecretWriter); ok {
+		secretWriter.SetContainsSecrets(r.ContainsSecrets())
+	}
 
 	if _, err := writer.Write([]byte(output)); err != nil {
 		return newWriteError(err)
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/eiladin/k8s-dotenv/pkg/options"
 	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -54,7 +55,7 @@ func Test_newResult(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			opt := []cmp.Option{
-				cmp.AllowUnexported(Result{}),
+				cmp.AllowUnexported(Result{}, source{}),
 				cmpopts.EquateErrors(),
 			}
 
@@ -136,10 +137,12 @@ func Test_configMapData(t *testing.T) {
 func Test_secretData(t *testing.T) {
 	kubeClient := mock.NewFakeClient(
 		mock.Secret("test", "test", map[string][]byte{"sec1": []byte("val"), "sec2": []byte("val2")}),
+		newTypedSecret("creds", v1.SecretTypeBasicAuth, map[string][]byte{"username": []byte("u"), "password": []byte("p")}),
 	)
 
 	type args struct {
 		client    kubernetes.Interface
+		output    options.Output
 		namespace string
 		resource  string
 	}
@@ -159,6 +162,25 @@ func Test_secretData(t *testing.T) {
 			},
 			want: map[string]string{"sec1": "val", "sec2": "val2"},
 		},
+		{
+			name: "keeps typed secret data",
+			args: args{
+				client:    kubeClient,
+				namespace: "test",
+				resource:  "creds",
+			},
+			want: map[string]string{"username": "u", "password": "p"},
+		},
+		{
+			name: "converts typed secret data",
+			args: args{
+				client:    kubeClient,
+				output:    options.Output{SecretTypes: true},
+				namespace: "test",
+				resource:  "creds",
+			},
+			want: map[string]string{"CREDS_USERNAME": "u", "CREDS_PASSWORD": "p"},
+		},
 		{
 			name: "returns error when resource not found",
 			args: args{
@@ -172,7 +194,7 @@ func Test_secretData(t *testing.T) {
 
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
-			got, err := secretData(testCase.args.client, testCase.args.namespace, testCase.args.resource)
+			got, _, err := secretData(testCase.args.client, testCase.args.output, testCase.args.namespace, testCase.args.resource)
 			if (err != nil) != testCase.wantErr {
 				t.Errorf("secretData() error = %v, wantErr %v", err, testCase.wantErr)
 
@@ -216,10 +238,9 @@ func TestNewFromContainers(t *testing.T) {
 	)
 
 	type args struct {
-		client       kubernetes.Interface
-		namespace    string
-		shouldExport bool
-		containers   []v1.Container
+		client     kubernetes.Interface
+		opt        *options.Client
+		containers []v1.Container
 	}
 
 	tests := []struct {
@@ -230,57 +251,80 @@ func TestNewFromContainers(t *testing.T) {
 		{
 			name: "create",
 			args: args{
-				client:    kubeClient,
-				namespace: "test",
+				client: kubeClient,
+				opt:    &options.Client{Namespace: "test", ShouldExport: true},
 				containers: []v1.Container{
 					mock.Container(map[string]string{"env1": "val", "env2": "val2"}, []string{"test"}, []string{"test"}),
 				},
-				shouldExport: true,
 			},
 			want: &Result{
 				shouldExport: true,
 				Environment:  EnvValues{"env1": "val", "env2": "val2"},
 				ConfigMaps:   map[string]EnvValues{"test": {"cm1": "val", "cm2": "val2"}},
 				Secrets:      map[string]EnvValues{"test": {"sec1": "val", "sec2": "val2"}},
+				envFrom:      []source{{kind: KindConfigMap, name: "test"}, {kind: KindSecret, name: "test"}},
 			},
 		},
 		{
 			name: "error on missing configmap",
 			args: args{
-				client:    kubeClient,
-				namespace: "test",
+				client: kubeClient,
+				opt:    &options.Client{Namespace: "test", ShouldExport: true},
 				containers: []v1.Container{
 					mock.Container(map[string]string{"env1": "val", "env2": "val2"}, []string{"test1"}, []string{"test"}),
 				},
-				shouldExport: true,
 			},
 			want: NewFromError(ErrMissingResource),
 		},
 		{
 			name: "error on missing secret",
 			args: args{
-				client:    kubeClient,
-				namespace: "test",
+				client: kubeClient,
+				opt:    &options.Client{Namespace: "test", ShouldExport: true},
 				containers: []v1.Container{
 					mock.Container(map[string]string{"env1": "val", "env2": "val2"}, []string{"test"}, []string{"test1"}),
 				},
-				shouldExport: true,
 			},
 			want: NewFromError(ErrMissingResource),
 		},
+		{
+			name: "apply filters",
+			args: args{
+				client: kubeClient,
+				opt:    &options.Client{Namespace: "test", Output: options.Output{Exclude: []string{"*2"}}},
+				containers: []v1.Container{
+					mock.Container(map[string]string{"env1": "val", "env2": "val2"}, []string{"test"}, []string{"test"}),
+				},
+			},
+			want: &Result{
+				output:      options.Output{Exclude: []string{"*2"}},
+				Environment: EnvValues{"env1": "val"},
+				ConfigMaps:  map[string]EnvValues{"test": {"cm1": "val"}},
+				Secrets:     map[string]EnvValues{"test": {"sec1": "val"}},
+				envFrom:     []source{{kind: KindConfigMap, name: "test"}, {kind: KindSecret, name: "test"}},
+			},
+		},
+		{
+			name: "error on invalid filter",
+			args: args{
+				client:     kubeClient,
+				opt:        &options.Client{Namespace: "test", Output: options.Output{Include: []string{"/(/"}}},
+				containers: []v1.Container{mock.Container(nil, nil, nil)},
+			},
+			want: NewFromError(ErrInvalidFilter),
+		},
 	}
 
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			opt := []cmp.Option{
-				cmp.AllowUnexported(Result{}),
+				cmp.AllowUnexported(Result{}, source{}),
 				cmpopts.EquateErrors(),
 			}
 
 			got := NewFromContainers(
 				testCase.args.client,
-				testCase.args.namespace,
-				testCase.args.shouldExport,
+				testCase.args.opt,
 				testCase.args.containers,
 			)
 
@@ -311,12 +355,28 @@ cm="val"
 sec="val"
 `,
 		},
+		{
+			name: "transforms",
+			r: &Result{
+				output:      options.Output{Include: []string{"env"}, Expand: []string{"env"}, SecretTypes: true},
+				Environment: EnvValues{"env": "val"},
+			},
+			want: "# k8s-dotenv: filtered, expanded, secret-types\nenv=\"val\"\n",
+		},
+		{
+			name: "redacted without secrets",
+			r: &Result{
+				output:      options.Output{Redact: true},
+				Environment: EnvValues{"env": "val"},
+			},
+			want: "env=\"val\"\n",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.r.parse(); got != tt.want {
-				t.Errorf("Result.parse() = %v, want %v", got, tt.want)
+			if got, _ := tt.r.render(); got != tt.want {
+				t.Errorf("Result.render() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -342,6 +402,11 @@ func TestResult_Write(t *testing.T) {
 			wantErr:   true,
 			nilWriter: true,
 		},
+		{
+			name:    "error on unsupported format",
+			r:       &Result{output: options.Output{Format: "unknown"}},
+			wantErr: true,
+		},
 		{
 			name:    "return writer error",
 			r:       &Result{},
@@ -389,3 +454,140 @@ sec="val"
 		})
 	}
 }
+
+func TestFormats(t *testing.T) {
+	t.Run("list formats", func(t *testing.T) {
+		got := Formats()
+		if len(got) != len(formats()) {
+			t.Errorf("Formats() = %v, want %d formats", got, len(formats()))
+		}
+	})
+}
+
+func Test_sourceName(t *testing.T) {
+	type args struct {
+		kind string
+		name string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "environment", args: args{}, want: "environment"},
+		{name: "secret", args: args{kind: KindSecret, name: "test"}, want: "secret test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sourceName(tt.args.kind, tt.args.name); got != tt.want {
+				t.Errorf("sourceName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type secretWriter struct {
+	secrets bool
+	written string
+}
+
+func (w *secretWriter) SetContainsSecrets(secrets bool) {
+	w.secrets = secrets
+}
+
+func (w *secretWriter) Write(data []byte) (int, error) {
+	w.written += string(data)
+
+	return len(data), nil
+}
+
+func TestResult_Write_secretWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Result
+		want bool
+	}{
+		{name: "with secrets", r: &Result{Secrets: map[string]EnvValues{"s": {"k": "v"}}}, want: true},
+		{name: "empty secret", r: &Result{Secrets: map[string]EnvValues{"s": {}}}, want: false},
+		{name: "without secrets", r: &Result{Environment: EnvValues{"k": "v"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &secretWriter{}
+			if err := tt.r.Write(writer); err != nil {
+				t.Fatalf("Result.Write() error = %v", err)
+			}
+
+			if writer.secrets != tt.want || tt.r.ContainsSecrets() != tt.want {
+				t.Errorf("Result.Write() secrets = %v, want %v", writer.secrets, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_Values(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Result
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "env overrides sources",
+			r: &Result{
+				Environment: EnvValues{"a": "env", "b": "env"},
+				ConfigMaps:  map[string]EnvValues{"cm": {"b": "cm", "c": "cm"}},
+				Secrets:     map[string]EnvValues{"s": {"c": "secret"}},
+			},
+			want: map[string]string{"a": "env", "b": "env", "c": "secret"},
+		},
+		{
+			name: "later envFrom sources win",
+			r: &Result{
+				Environment: EnvValues{},
+				ConfigMaps:  map[string]EnvValues{"cm": {"c": "cm"}},
+				Secrets:     map[string]EnvValues{"s": {"c": "secret"}},
+				envFrom:     []source{{kind: KindSecret, name: "s"}, {kind: KindConfigMap, name: "cm"}},
+			},
+			want: map[string]string{"c": "cm"},
+		},
+		{name: "error", r: NewFromError(mock.AnError), wantErr: mock.AnError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.Values()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Result.Values() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("Result.Values() = %v", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestNewFromContainers_precedence(t *testing.T) {
+	kubeClient := mock.NewFakeClient(
+		mock.ConfigMap("cm", "test", map[string]string{"both": "cm", "shared": "cm"}),
+		mock.Secret("sec", "test", map[string][]byte{"shared": []byte("secret")}),
+	)
+	container := mock.Container(map[string]string{"both": "env"}, nil, nil)
+	container.EnvFrom = []v1.EnvFromSource{
+		{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "sec"}}},
+		{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "cm"}}},
+	}
+
+	got, err := NewFromContainers(kubeClient, &options.Client{Namespace: "test"}, []v1.Container{container}).Values()
+	if err != nil {
+		t.Fatalf("Result.Values() error = %v", err)
+	}
+
+	if want := map[string]string{"both": "env", "shared": "cm"}; !cmp.Equal(got, want) {
+		t.Errorf("Result.Values() = %v", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,141 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// ErrContainerRequired is returned when a workload has more than one container and none was selected.
+var ErrContainerRequired = errors.New("workload has multiple containers, select one with --container")
+
+// scriptContainer returns the container reproduced by the script and docker formats.
+func (r *Result) scriptContainer() (*corev1.Container, error) {
+	if r.Workload == nil {
+		return nil, ErrMissingWorkload
+	}
+
+	spec := PodSpec(r.Workload)
+	if spec == nil {
+		return nil, ErrUnsupportedWorkload
+	}
+
+	if r.container != "" {
+		if container := findContainer(spec.Containers, r.container); container != nil {
+			return container, nil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrMissingContainer, r.container)
+	}
+
+	if len(spec.Containers) != 1 {
+		return nil, ErrContainerRequired
+	}
+
+	return &spec.Containers[0], nil
+}
+
+func (r *Result) scriptDescription(container *corev1.Container) string {
+	name := defaultManifestName
+	if accessor, err := meta.Accessor(r.Workload); err == nil {
+		name = accessor.GetName()
+	}
+
+	return fmt.Sprintf("# %s container %s (%s)\n", name, container.Name, container.Image)
+}
+
+// invocation returns the container command and args with `$(VAR)` references expanded like the kubelet does.
+func (r *Result) invocation(container *corev1.Container) ([]string, []string) {
+	env := r.environment()
+
+	return parser.ExpandAll(container.Command, env), parser.ExpandAll(container.Args, env)
+}
+
+func (r *Result) parseScript() (string, error) {
+	container, err := r.scriptContainer()
+	if err != nil {
+		return "", err
+	}
+
+	res := "#!/bin/sh\n" + r.scriptDescription(container)
+
+	res += r.lines(func(kind, name string) string {
+		return fmt.Sprintf("# %s - %s\n", kind, name)
+	}, parser.Shell)
+
+	if container.WorkingDir != "" {
+		res += fmt.Sprintf("cd %s || exit 1\n", parser.ShellQuote(container.WorkingDir))
+	}
+
+	command, args := r.invocation(container)
+
+	switch {
+	case len(command) == 0 && len(args) == 0:
+		res += "# the image entrypoint and command are used, pass them as arguments\n"
+		res += "exec \"$@\"\n"
+	case len(command) == 0:
+		res += "# the image entrypoint is used, prepend it to the arguments below\n"
+		res += fmt.Sprintf("exec %s\n", parser.ShellCommand(args))
+	default:
+		res += fmt.Sprintf("exec %s\n", parser.ShellCommand(append(command, args...)))
+	}
+
+	return res, nil
+}
+
+func (r *Result) parseDocker() (string, error) {
+	container, err := r.scriptContainer()
+	if err != nil {
+		return "", err
+	}
+
+	words := []string{"docker", "run", "--rm", "-it"}
+
+	env := r.environment()
+	for _, key := range EnvValues(env).sortedKeys() {
+		words = append(words, "-e", key+"="+env[key])
+	}
+
+	if container.WorkingDir != "" {
+		words = append(words, "-w", container.WorkingDir)
+	}
+
+	command, args := r.invocation(container)
+
+	if len(command) > 0 {
+		words = append(words, "--entrypoint", command[0])
+	}
+
+	words = append(words, container.Image)
+
+	if len(command) > 1 {
+		words = append(words, command[1:]...)
+	}
+
+	words = append(words, args...)
+
+	return strings.Join(quoteWords(words), " ") + "\n", nil
+}
+
+// quoteWords quotes the words that need it so the command line stays readable.
+func quoteWords(words []string) []string {
+	res := make([]string, 0, len(words))
+
+	for _, word := range words {
+		if word != "" && strings.IndexFunc(word, needsQuote) == -1 {
+			res = append(res, word)
+		} else {
+			res = append(res, parser.ShellQuote(word))
+		}
+	}
+
+	return res
+}
+
+func needsQuote(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+}
@@ -0,0 +1,147 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func scriptPod(containers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       corev1.PodSpec{Containers: containers},
+	}
+}
+
+func TestResult_parseScript(t *testing.T) {
+	container := corev1.Container{
+		Name:       "api",
+		Image:      "example/api:1.0",
+		WorkingDir: "/app",
+		Command:    []string{"/bin/api"},
+		Args:       []string{"--port=$(PORT)", "$$(PORT)"},
+	}
+
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr error
+	}{
+		{
+			name: "command and args",
+			r: &Result{
+				Workload:    scriptPod(container),
+				Environment: EnvValues{"PORT": "8080"},
+				Secrets:     map[string]EnvValues{"db": {"PASSWORD": "it's"}},
+			},
+			want: `#!/bin/sh
+# api container api (example/api:1.0)
+export PORT='8080'
+# SECRET - db
+export PASSWORD='it'\''s'
+cd '/app' || exit 1
+exec '/bin/api' '--port=8080' '$(PORT)'
+`,
+		},
+		{
+			name: "image entrypoint",
+			r: &Result{
+				Workload:    scriptPod(corev1.Container{Name: "api", Image: "example/api:1.0"}),
+				Environment: EnvValues{},
+			},
+			want: `#!/bin/sh
+# api container api (example/api:1.0)
+# the image entrypoint and command are used, pass them as arguments
+exec "$@"
+`,
+		},
+		{
+			name: "selected container",
+			r: &Result{
+				Workload:    scriptPod(corev1.Container{Name: "sidecar"}, corev1.Container{Name: "api", Args: []string{"serve"}}),
+				Environment: EnvValues{},
+				container:   "api",
+			},
+			want: `#!/bin/sh
+# api container api ()
+# the image entrypoint is used, prepend it to the arguments below
+exec 'serve'
+`,
+		},
+		{
+			name:    "error without workload",
+			r:       &Result{},
+			wantErr: ErrMissingWorkload,
+		},
+		{
+			name:    "error with multiple containers",
+			r:       &Result{Workload: scriptPod(container, container)},
+			wantErr: ErrContainerRequired,
+		},
+		{
+			name:    "error with missing container",
+			r:       &Result{Workload: scriptPod(container), container: "missing"},
+			wantErr: ErrMissingContainer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseScript()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.parseScript() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Result.parseScript() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_parseDocker(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr error
+	}{
+		{
+			name: "docker run",
+			r: &Result{
+				Workload: scriptPod(corev1.Container{
+					Name:       "api",
+					Image:      "example/api:1.0",
+					WorkingDir: "/app",
+					Command:    []string{"/bin/api", "serve"},
+					Args:       []string{"--greeting=$(GREETING)"},
+				}),
+				Environment: EnvValues{"GREETING": "hello world"},
+				ConfigMaps:  map[string]EnvValues{"cfg": {"PORT": "8080"}},
+			},
+			want: "docker run --rm -it -e 'GREETING=hello world' -e PORT=8080 -w /app " +
+				"--entrypoint /bin/api example/api:1.0 serve '--greeting=hello world'\n",
+		},
+		{
+			name:    "error without workload",
+			r:       &Result{},
+			wantErr: ErrMissingWorkload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseDocker()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.parseDocker() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Result.parseDocker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package result
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrInvalidSecret is returned when a Secret of a well-known type does not hold the data its type requires.
+var ErrInvalidSecret = errors.New("invalid secret")
+
+// ErrDockerConfigConflict is returned when several Secrets would set `DOCKER_CONFIG`, docker only reads one directory.
+var ErrDockerConfigConflict = errors.New("several secrets set DOCKER_CONFIG")
+
+// secretFilesDir is the directory, next to the output, the files of typed Secrets are written to.
+const secretFilesDir = ".secrets"
+
+// dockerConfigKey is the variable docker reads the directory of its config file from.
+const dockerConfigKey = "DOCKER_CONFIG"
+
+// secretFile is a file written next to the output with a Secret value, such as a TLS key.
+type secretFile struct {
+	name string
+	data []byte
+}
+
+// typedSecret is what a Secret type handler converts a Secret into, the values can refer to the files.
+type typedSecret struct {
+	values EnvValues
+	files  []secretFile
+}
+
+// addFile adds a file for the Secret name and returns the path it will have.
+func (s *typedSecret) addFile(output options.Output, name, file string, data []byte) string {
+	filePath := path.Join(secretFilesDir, name, file)
+	s.files = append(s.files, secretFile{name: filePath, data: data})
+
+	return filepath.Join(output.FileDir, filepath.FromSlash(filePath))
+}
+
+type secretHandler func(name string, data map[string][]byte, output options.Output) (*typedSecret, error)
+
+// secretHandlers convert Secrets of well-known types into conventional variables and files, keyed on `Secret.Type`.
+func secretHandlers() map[corev1.SecretType]secretHandler {
+	return map[corev1.SecretType]secretHandler{
+		corev1.SecretTypeBasicAuth:        basicAuthSecret,
+		corev1.SecretTypeDockercfg:        dockerConfigSecret,
+		corev1.SecretTypeDockerConfigJson: dockerConfigSecret,
+		corev1.SecretTypeSSHAuth:          sshAuthSecret,
+		corev1.SecretTypeTLS:              tlsSecret,
+	}
+}
+
+// secretPrefix is the prefix of the variables of a typed Secret, `registry-creds` becomes `REGISTRY_CREDS`.
+func secretPrefix(name string, parts ...string) string {
+	return parser.EnvKey(append([]string{name}, parts...), "_")
+}
+
+func requireKeys(name string, data map[string][]byte, keys ...string) error {
+	for _, key := range keys {
+		if _, found := data[key]; !found {
+			return fmt.Errorf("%w: %s has no %s", ErrInvalidSecret, name, key)
+		}
+	}
+
+	return nil
+}
+
+// basicAuthSecret sets `NAME_USERNAME` and `NAME_PASSWORD`.
+func basicAuthSecret(name string, data map[string][]byte, _ options.Output) (*typedSecret, error) {
+	if err := requireKeys(name, data, corev1.BasicAuthUsernameKey); err != nil {
+		return nil, err
+	}
+
+	return &typedSecret{values: EnvValues{
+		secretPrefix(name, "USERNAME"): string(data[corev1.BasicAuthUsernameKey]),
+		secretPrefix(name, "PASSWORD"): string(data[corev1.BasicAuthPasswordKey]),
+	}}, nil
+}
+
+// sshAuthSecret writes the private key to a file and sets `NAME_SSH_KEY_FILE` to its path.
+func sshAuthSecret(name string, data map[string][]byte, output options.Output) (*typedSecret, error) {
+	if err := requireKeys(name, data, corev1.SSHAuthPrivateKey); err != nil {
+		return nil, err
+	}
+
+	key := data[corev1.SSHAuthPrivateKey]
+	if len(key) > 0 && key[len(key)-1] != '\n' {
+		// ssh refuses private keys without a trailing newline.
+		key = append(key[:len(key):len(key)], '\n')
+	}
+
+	res := &typedSecret{values: EnvValues{}}
+	res.values[secretPrefix(name, "SSH_KEY_FILE")] = res.addFile(output, name, "id", key)
+
+	return res, nil
+}
+
+// tlsSecret writes the certificate, key and CA certificate when there is one to files and sets `NAME_TLS_CERT_FILE`,
+// `NAME_TLS_KEY_FILE` and `NAME_TLS_CA_FILE` to their paths.
+func tlsSecret(name string, data map[string][]byte, output options.Output) (*typedSecret, error) {
+	if err := requireKeys(name, data, corev1.TLSCertKey, corev1.TLSPrivateKeyKey); err != nil {
+		return nil, err
+	}
+
+	res := &typedSecret{values: EnvValues{}}
+	res.values[secretPrefix(name, "TLS_CERT_FILE")] = res.addFile(output, name, corev1.TLSCertKey, data[corev1.TLSCertKey])
+	res.values[secretPrefix(name, "TLS_KEY_FILE")] = res.addFile(output, name, corev1.TLSPrivateKeyKey,
+		data[corev1.TLSPrivateKeyKey])
+
+	if ca, found := data[corev1.ServiceAccountRootCAKey]; found {
+		res.values[secretPrefix(name, "TLS_CA_FILE")] = res.addFile(output, name, corev1.ServiceAccountRootCAKey, ca)
+	}
+
+	return res, nil
+}
+
+// dockerAuth is a registry entry of a docker config file.
+type dockerAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// credentials returns the username and password, decoding them from auth when they are not set.
+func (a dockerAuth) credentials() (string, string, error) {
+	if a.Username != "" || a.Auth == "" {
+		return a.Username, a.Password, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+	if err != nil {
+		return "", "", fmt.Errorf("decoding auth: %w", err)
+	}
+
+	username, password, _ := strings.Cut(string(decoded), ":")
+
+	return username, password, nil
+}
+
+// dockerAuths returns the registries of a dockerconfigjson Secret, or of a legacy dockercfg Secret.
+func dockerAuths(name string, data map[string][]byte) (map[string]dockerAuth, error) {
+	if config, found := data[corev1.DockerConfigJsonKey]; found {
+		var res struct {
+			Auths map[string]dockerAuth `json:"auths"`
+		}
+
+		if err := json.Unmarshal(config, &res); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrInvalidSecret, name, err.Error())
+		}
+
+		return res.Auths, nil
+	}
+
+	if err := requireKeys(name, data, corev1.DockerConfigKey); err != nil {
+		return nil, err
+	}
+
+	var res map[string]dockerAuth
+	if err := json.Unmarshal(data[corev1.DockerConfigKey], &res); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidSecret, name, err.Error())
+	}
+
+	return res, nil
+}
+
+// dockerConfigSecret sets `NAME_REGISTRY`, `NAME_USERNAME` and `NAME_PASSWORD`, numbered from `NAME_0_REGISTRY` in
+// registry order when there is more than one registry. With `DockerConfig` it writes the docker config file instead
+// and sets `DOCKER_CONFIG` to its directory.
+func dockerConfigSecret(name string, data map[string][]byte, output options.Output) (*typedSecret, error) {
+	auths, err := dockerAuths(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &typedSecret{values: EnvValues{}}
+
+	if output.DockerConfig {
+		config, found := data[corev1.DockerConfigJsonKey]
+		if !found {
+			config = []byte(`{"auths":` + string(data[corev1.DockerConfigKey]) + `}`)
+		}
+
+		res.values[dockerConfigKey] = filepath.Dir(res.addFile(output, name, "config.json", config))
+
+		return res, nil
+	}
+
+	registries := make([]string, 0, len(auths))
+	for registry := range auths {
+		registries = append(registries, registry)
+	}
+
+	sort.Strings(registries)
+
+	for i, registry := range registries {
+		username, password, err := auths[registry].credentials()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s: %s", ErrInvalidSecret, name, registry, err.Error())
+		}
+
+		prefix := secretPrefix(name)
+		if len(registries) > 1 {
+			prefix = secretPrefix(name, strconv.Itoa(i))
+		}
+
+		res.values[prefix+"_REGISTRY"] = registry
+		res.values[prefix+"_USERNAME"] = username
+		res.values[prefix+"_PASSWORD"] = password
+	}
+
+	return res, nil
+}
+
+// typedSecretData converts a Secret with the handler for its type. The values are nil when the Secret keeps its data,
+// because its type has no handler or because it needs files and the output has no file writer.
+func typedSecretData(secret *corev1.Secret, output options.Output) (EnvValues, []secretFile, error) {
+	handler, found := secretHandlers()[secret.Type]
+	if !found {
+		return nil, nil, nil
+	}
+
+	res, err := handler(secret.Name, secret.Data, output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(res.files) > 0 && output.WriteFile == nil {
+		return nil, nil, nil
+	}
+
+	return res.values, res.files, nil
+}
+
+// checkDockerConfig returns an error when more than one Secret sets `DOCKER_CONFIG`, the last one would silently win.
+func checkDockerConfig(secrets map[string]EnvValues) error {
+	names := []string{}
+
+	for name, values := range secrets {
+		if _, found := values[dockerConfigKey]; found {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) > 1 {
+		sort.Strings(names)
+
+		return fmt.Errorf("%w: %s", ErrDockerConfigConflict, strings.Join(names, ", "))
+	}
+
+	return nil
+}
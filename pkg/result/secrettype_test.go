@@ -0,0 +1,239 @@
+package result
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newTypedSecret(name string, secretType corev1.SecretType, data map[string][]byte) *corev1.Secret {
+	secret := mock.Secret(name, "test", data)
+	secret.Type = secretType
+
+	return secret
+}
+
+func Test_typedSecretData(t *testing.T) {
+	dir := filepath.Join("home", "dev")
+	writeFile := func(name string, data []byte, perm os.FileMode) error { return nil }
+	output := options.Output{SecretTypes: true, FileDir: dir, WriteFile: writeFile}
+	auth := base64.StdEncoding.EncodeToString([]byte("user:pa:ss"))
+	dockerConfig := []byte(`{"auths":{"ghcr.io":{"auth":"` + auth + `"}}}`)
+
+	tests := []struct {
+		name      string
+		secret    *corev1.Secret
+		output    options.Output
+		want      EnvValues
+		wantFiles []secretFile
+		wantErr   bool
+	}{
+		{
+			name:   "opaque",
+			secret: newTypedSecret("app", corev1.SecretTypeOpaque, map[string][]byte{"k": []byte("v")}),
+			output: output,
+		},
+		{
+			name: "basic-auth",
+			secret: newTypedSecret("db-creds", corev1.SecretTypeBasicAuth, map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("admin"),
+				corev1.BasicAuthPasswordKey: []byte("secret"),
+			}),
+			output: output,
+			want:   EnvValues{"DB_CREDS_USERNAME": "admin", "DB_CREDS_PASSWORD": "secret"},
+		},
+		{
+			name:    "error on basic-auth without username",
+			secret:  newTypedSecret("db-creds", corev1.SecretTypeBasicAuth, nil),
+			output:  output,
+			wantErr: true,
+		},
+		{
+			name:      "ssh-auth",
+			secret:    newTypedSecret("git", corev1.SecretTypeSSHAuth, map[string][]byte{corev1.SSHAuthPrivateKey: []byte("KEY")}),
+			output:    output,
+			want:      EnvValues{"GIT_SSH_KEY_FILE": filepath.Join(dir, ".secrets", "git", "id")},
+			wantFiles: []secretFile{{name: ".secrets/git/id", data: []byte("KEY\n")}},
+		},
+		{
+			name: "tls",
+			secret: newTypedSecret("api-tls", corev1.SecretTypeTLS, map[string][]byte{
+				corev1.TLSCertKey:              []byte("CERT"),
+				corev1.TLSPrivateKeyKey:        []byte("KEY"),
+				corev1.ServiceAccountRootCAKey: []byte("CA"),
+			}),
+			output: output,
+			want: EnvValues{
+				"API_TLS_TLS_CERT_FILE": filepath.Join(dir, ".secrets", "api-tls", "tls.crt"),
+				"API_TLS_TLS_KEY_FILE":  filepath.Join(dir, ".secrets", "api-tls", "tls.key"),
+				"API_TLS_TLS_CA_FILE":   filepath.Join(dir, ".secrets", "api-tls", "ca.crt"),
+			},
+			wantFiles: []secretFile{
+				{name: ".secrets/api-tls/tls.crt", data: []byte("CERT")},
+				{name: ".secrets/api-tls/tls.key", data: []byte("KEY")},
+				{name: ".secrets/api-tls/ca.crt", data: []byte("CA")},
+			},
+		},
+		{
+			name:    "error on tls without key",
+			secret:  newTypedSecret("api-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: []byte("CERT")}),
+			output:  output,
+			wantErr: true,
+		},
+		{
+			name:   "tls without a file writer",
+			secret: newTypedSecret("api-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: nil, corev1.TLSPrivateKeyKey: nil}),
+			output: options.Output{SecretTypes: true},
+		},
+		{
+			name:   "dockerconfigjson",
+			secret: newTypedSecret("pull", corev1.SecretTypeDockerConfigJson, map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig}),
+			output: output,
+			want:   EnvValues{"PULL_REGISTRY": "ghcr.io", "PULL_USERNAME": "user", "PULL_PASSWORD": "pa:ss"},
+		},
+		{
+			name: "dockercfg with several registries",
+			secret: newTypedSecret("pull", corev1.SecretTypeDockercfg, map[string][]byte{
+				corev1.DockerConfigKey: []byte(`{"b.io":{"username":"b","password":"pb"},"a.io":{"username":"a","password":"pa"}}`),
+			}),
+			output: output,
+			want: EnvValues{
+				"PULL_0_REGISTRY": "a.io", "PULL_0_USERNAME": "a", "PULL_0_PASSWORD": "pa",
+				"PULL_1_REGISTRY": "b.io", "PULL_1_USERNAME": "b", "PULL_1_PASSWORD": "pb",
+			},
+		},
+		{
+			name:      "dockerconfigjson as a docker config file",
+			secret:    newTypedSecret("pull", corev1.SecretTypeDockerConfigJson, map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig}),
+			output:    options.Output{SecretTypes: true, DockerConfig: true, FileDir: dir, WriteFile: writeFile},
+			want:      EnvValues{"DOCKER_CONFIG": filepath.Join(dir, ".secrets", "pull")},
+			wantFiles: []secretFile{{name: ".secrets/pull/config.json", data: dockerConfig}},
+		},
+		{
+			name:      "dockercfg as a docker config file",
+			secret:    newTypedSecret("pull", corev1.SecretTypeDockercfg, map[string][]byte{corev1.DockerConfigKey: []byte(`{"a.io":{}}`)}),
+			output:    options.Output{SecretTypes: true, DockerConfig: true, WriteFile: writeFile},
+			want:      EnvValues{"DOCKER_CONFIG": filepath.Join(".secrets", "pull")},
+			wantFiles: []secretFile{{name: ".secrets/pull/config.json", data: []byte(`{"auths":{"a.io":{}}}`)}},
+		},
+		{
+			name:    "error on invalid docker config",
+			secret:  newTypedSecret("pull", corev1.SecretTypeDockerConfigJson, map[string][]byte{corev1.DockerConfigJsonKey: []byte("{")}),
+			output:  output,
+			wantErr: true,
+		},
+		{
+			name:    "error on invalid docker auth",
+			secret:  newTypedSecret("pull", corev1.SecretTypeDockerConfigJson, map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{"a.io":{"auth":"!"}}}`)}),
+			output:  output,
+			wantErr: true,
+		},
+		{
+			name:    "error on dockercfg without data",
+			secret:  newTypedSecret("pull", corev1.SecretTypeDockercfg, nil),
+			output:  output,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, files, err := typedSecretData(tt.secret, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("typedSecretData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, ErrInvalidSecret) {
+				t.Errorf("typedSecretData() error = %v, want %v", err, ErrInvalidSecret)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("typedSecretData() = %v", cmp.Diff(tt.want, got))
+			}
+
+			if !cmp.Equal(files, tt.wantFiles, cmp.AllowUnexported(secretFile{})) {
+				t.Errorf("typedSecretData() files = %v", cmp.Diff(tt.wantFiles, files, cmp.AllowUnexported(secretFile{})))
+			}
+		})
+	}
+}
+
+func TestResult_Write_secretTypes(t *testing.T) {
+	kubeClient := mock.NewFakeClient(
+		newTypedSecret("git", corev1.SecretTypeSSHAuth, map[string][]byte{corev1.SSHAuthPrivateKey: []byte("KEY\n")}),
+	)
+	written := map[string]string{}
+	output := options.Output{
+		SecretTypes: true,
+		WriteFile: func(name string, data []byte, perm os.FileMode) error {
+			if perm != SecretFilePerm {
+				t.Errorf("WriteFile() perm = %v, want %v", perm, SecretFilePerm)
+			}
+
+			written[name] = string(data)
+
+			return nil
+		},
+	}
+
+	res := NewFromContainers(kubeClient, &options.Client{Namespace: "test", Output: output},
+		[]corev1.Container{mock.Container(nil, nil, []string{"git"})})
+
+	writer := mock.NewWriter()
+	if err := res.Write(writer); err != nil {
+		t.Fatalf("Result.Write() error = %v", err)
+	}
+
+	want := "# k8s-dotenv: secret-types\n##### SECRET - git #####\nGIT_SSH_KEY_FILE=\"" + filepath.Join(".secrets", "git", "id") + "\"\n"
+	if got := writer.String(); got != want {
+		t.Errorf("Result.Write() = %q, want %q", got, want)
+	}
+
+	if !cmp.Equal(written, map[string]string{".secrets/git/id": "KEY\n"}) {
+		t.Errorf("Result.Write() files = %v", written)
+	}
+
+	res.output.WriteFile = func(name string, data []byte, perm os.FileMode) error { return mock.AnError }
+	if err := res.Write(mock.NewWriter()); !errors.Is(err, mock.AnError) {
+		t.Errorf("Result.Write() error = %v, want %v", err, mock.AnError)
+	}
+
+	res.output.Redact = true
+	if err := res.Write(mock.NewWriter()); err != nil {
+		t.Errorf("Result.Write() redacted error = %v, want no files written", err)
+	}
+}
+
+func TestNewFromContainers_dockerConfigConflict(t *testing.T) {
+	config := map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{"a.io":{}}}`)}
+	kubeClient := mock.NewFakeClient(
+		newTypedSecret("a", corev1.SecretTypeDockerConfigJson, config),
+		newTypedSecret("b", corev1.SecretTypeDockerConfigJson, config),
+	)
+	output := options.Output{
+		SecretTypes:  true,
+		DockerConfig: true,
+		WriteFile:    func(string, []byte, os.FileMode) error { return nil },
+	}
+
+	res := NewFromContainers(kubeClient, &options.Client{Namespace: "test", Output: output},
+		[]corev1.Container{mock.Container(nil, nil, []string{"a", "b"})})
+	if !errors.Is(res.Error, ErrDockerConfigConflict) {
+		t.Errorf("NewFromContainers() error = %v, want %v", res.Error, ErrDockerConfigConflict)
+	}
+
+	output.DockerConfig = false
+
+	res = NewFromContainers(kubeClient, &options.Client{Namespace: "test", Output: output},
+		[]corev1.Container{mock.Container(nil, nil, []string{"a", "b"})})
+	if res.Error != nil {
+		t.Errorf("NewFromContainers() error = %v, want registry variables per secret", res.Error)
+	}
+}
@@ -0,0 +1,34 @@
+package result
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+func systemdUnitName(unit string) string {
+	if strings.Contains(unit, ".") {
+		return unit
+	}
+
+	return unit + ".service"
+}
+
+func systemdHeader(kind, name string) string {
+	return fmt.Sprintf("# %s - %s\n", kind, name)
+}
+
+// parseSystemd renders an `EnvironmentFile=` or, when a unit is configured, a drop-in with `Environment=` directives.
+func (r *Result) parseSystemd() (string, error) {
+	if r.output.SystemdUnit == "" {
+		return r.lines(sectionHeader, parser.Systemd), nil
+	}
+
+	unit := systemdUnitName(r.output.SystemdUnit)
+	res := fmt.Sprintf("# drop-in for %[1]s, save as ~/.config/systemd/user/%[1]s.d/k8s-dotenv.conf\n", unit)
+	res += "[Service]\n"
+	res += r.lines(systemdHeader, parser.SystemdEnvironment)
+
+	return res, nil
+}
@@ -0,0 +1,74 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+)
+
+func Test_systemdUnitName(t *testing.T) {
+	tests := []struct {
+		name string
+		unit string
+		want string
+	}{
+		{name: "add service suffix", unit: "api", want: "api.service"},
+		{name: "keep unit type", unit: "api.socket", want: "api.socket"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := systemdUnitName(tt.unit); got != tt.want {
+				t.Errorf("systemdUnitName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_parseSystemd(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Result
+		want string
+	}{
+		{
+			name: "environment file",
+			r: &Result{
+				Environment: EnvValues{"env": "$val"},
+				ConfigMaps:  map[string]EnvValues{"test": {"cm": "val"}},
+				Secrets:     map[string]EnvValues{"test": {"sec": "val"}},
+			},
+			want: `env="\$val"
+##### CONFIGMAP - test #####
+cm="val"
+##### SECRET - test #####
+sec="val"
+`,
+		},
+		{
+			name: "drop-in",
+			r: &Result{
+				output:      options.Output{SystemdUnit: "api"},
+				Environment: EnvValues{"env": "val"},
+				ConfigMaps:  map[string]EnvValues{"test": {"cm": "val"}},
+				Secrets:     map[string]EnvValues{"test": {"sec": "val"}},
+			},
+			want: `# drop-in for api.service, save as ~/.config/systemd/user/api.service.d/k8s-dotenv.conf
+[Service]
+Environment="env=val"
+# CONFIGMAP - test
+Environment="cm=val"
+# SECRET - test
+Environment="sec=val"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := tt.r.parseSystemd(); got != tt.want {
+				t.Errorf("Result.parseSystemd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
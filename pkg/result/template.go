@@ -0,0 +1,79 @@
+package result
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+// ErrMissingTemplate is returned when the template format is used without a template.
+var ErrMissingTemplate = errors.New("missing template")
+
+// ErrTemplateArgument is returned when a template function is called with an unsupported argument.
+var ErrTemplateArgument = errors.New("unsupported template argument")
+
+func newTemplateError(err error) error {
+	return fmt.Errorf("template error: %w", err)
+}
+
+func templateJSON(value interface{}) (string, error) {
+	res, err := json.Marshal(value)
+	if err != nil {
+		return "", newTemplateError(err)
+	}
+
+	return string(res), nil
+}
+
+func templateSortedKeys(values interface{}) ([]string, error) {
+	switch values := values.(type) {
+	case EnvValues:
+		return values.sortedKeys(), nil
+	case map[string]string:
+		return EnvValues(values).sortedKeys(), nil
+	case map[string]EnvValues:
+		return sortedNames(values), nil
+	default:
+		return nil, fmt.Errorf("%w: sortedKeys of %T", ErrTemplateArgument, values)
+	}
+}
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"shellQuote": parser.ShellQuote,
+		"json":       templateJSON,
+		"base64": func(value string) string {
+			return base64.StdEncoding.EncodeToString([]byte(value))
+		},
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"sortedKeys": templateSortedKeys,
+	}
+}
+
+// parseTemplate renders the result through a user supplied text/template.
+//
+// The template receives the result, `.Entries` lists every value with the kind and name of its source.
+func (r *Result) parseTemplate() (string, error) {
+	if r.output.Template == "" {
+		return "", ErrMissingTemplate
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs()).Option("missingkey=error").Parse(r.output.Template)
+	if err != nil {
+		return "", newTemplateError(err)
+	}
+
+	var res bytes.Buffer
+	if err := tmpl.Execute(&res, r); err != nil {
+		return "", newTemplateError(err)
+	}
+
+	return res.String(), nil
+}
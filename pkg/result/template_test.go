@@ -0,0 +1,82 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+)
+
+func Test_templateSortedKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  interface{}
+		want    []string
+		wantErr error
+	}{
+		{name: "env values", values: EnvValues{"b": "", "a": ""}, want: []string{"a", "b"}},
+		{name: "string map", values: map[string]string{"b": "", "a": ""}, want: []string{"a", "b"}},
+		{name: "sources", values: map[string]EnvValues{"b": {}, "a": {}}, want: []string{"a", "b"}},
+		{name: "unsupported", values: 1, wantErr: ErrTemplateArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templateSortedKeys(tt.values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("templateSortedKeys() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("templateSortedKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_parseTemplate(t *testing.T) {
+	res := Result{
+		Environment: EnvValues{"env": "it's"},
+		ConfigMaps:  map[string]EnvValues{"app": {"cm": "val"}},
+		Secrets:     map[string]EnvValues{"db": {"sec": "val"}},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "entries with metadata",
+			template: `{{ range .Entries }}{{ .Kind }} {{ .Source }} {{ .Key }}={{ shellQuote .Value }} {{ .IsSecret }}` + "\n{{ end }}",
+			want:     "ENVIRONMENT  env='it'\\''s' false\nCONFIGMAP app cm='val' false\nSECRET db sec='val' true\n",
+		},
+		{
+			name:     "helpers",
+			template: `{{ range sortedKeys .Secrets }}{{ upper . }} {{ base64 (index $.Secrets . "sec") }} {{ json $.Environment }}{{ end }}`,
+			want:     `DB dmFs {"env":"it's"}`,
+		},
+		{name: "error on missing template", wantErr: true},
+		{name: "return parse errors", template: "{{ .Missing", wantErr: true},
+		{name: "return execute errors", template: "{{ .Missing }}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := res
+			r.output = options.Output{Template: tt.template}
+
+			got, err := r.parseTemplate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Result.parseTemplate() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Result.parseTemplate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
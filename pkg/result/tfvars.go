@@ -0,0 +1,51 @@
+package result
+
+import "github.com/eiladin/k8s-dotenv/pkg/parser"
+
+func sameKey(key string) string {
+	return key
+}
+
+// parseTFVars renders a Terraform .tfvars file, with a variable per key or a single map variable when
+// `TFVarsVariable` is set.
+//
+// Terraform does not allow a key to be defined twice, so only the last value of a key is kept.
+func (r *Result) parseTFVars() (string, error) {
+	var res, current string
+
+	indent := ""
+	if r.output.TFVarsVariable != "" {
+		indent = "  "
+	}
+
+	err := r.eachLast(sameKey, func(kind, name, key, value string) error {
+		if source := sourceName(kind, name); kind != "" && source != current {
+			res += indent + configHeader(kind, name)
+			current = source
+		}
+
+		if r.output.TFVarsVariable != "" {
+			res += parser.TFVarsMapEntry(key, value)
+
+			return nil
+		}
+
+		line, err := parser.TFVars(key, value)
+		if err != nil {
+			return err
+		}
+
+		res += line
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if r.output.TFVarsVariable != "" {
+		return parser.TFVarsMap(r.output.TFVarsVariable, res)
+	}
+
+	return res, nil
+}
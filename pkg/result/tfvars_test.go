@@ -0,0 +1,71 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/parser"
+)
+
+func TestResult_parseTFVars(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Result
+		want    string
+		wantErr error
+	}{
+		{
+			name: "variable per key",
+			r: &Result{
+				Environment: EnvValues{"env": "val", "dup": "env"},
+				ConfigMaps:  map[string]EnvValues{"test": {"cm": "val", "dup": "cm"}},
+				Secrets:     map[string]EnvValues{"test": {"sec": "val"}},
+			},
+			want: `dup = "env"
+env = "val"
+# CONFIGMAP - test
+cm = "val"
+# SECRET - test
+sec = "val"
+`,
+		},
+		{
+			name: "map variable",
+			r: &Result{
+				output:      options.Output{TFVarsVariable: "app_env"},
+				Environment: EnvValues{"env": "val"},
+				Secrets:     map[string]EnvValues{"test": {"sec": "${val}"}},
+			},
+			want: `app_env = {
+  "env" = "val"
+  # SECRET - test
+  "sec" = "$${val}"
+}
+`,
+		},
+		{
+			name:    "error on invalid key",
+			r:       &Result{Environment: EnvValues{"my.key": "val"}},
+			wantErr: parser.ErrInvalidKey,
+		},
+		{
+			name:    "error on invalid variable",
+			r:       &Result{output: options.Output{TFVarsVariable: "app env"}},
+			wantErr: parser.ErrInvalidKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.parseTFVars()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Result.parseTFVars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Result.parseTFVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
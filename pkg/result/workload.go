@@ -0,0 +1,82 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrUnsupportedWorkload is returned when a pod spec cannot be found on a workload.
+var ErrUnsupportedWorkload = errors.New("unsupported workload")
+
+// ErrMissingContainer is returned when the requested container does not exist in the workload.
+var ErrMissingContainer = errors.New("container not found")
+
+// PodSpec returns the pod spec of a workload or nil when the workload type is not supported.
+func PodSpec(workload runtime.Object) *corev1.PodSpec {
+	switch obj := workload.(type) {
+	case *corev1.Pod:
+		return &obj.Spec
+	case *appsv1.Deployment:
+		return &obj.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		return &obj.Spec.Template.Spec
+	case *appsv1.ReplicaSet:
+		return &obj.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		return &obj.Spec.Template.Spec
+	case *batchv1.Job:
+		return &obj.Spec.Template.Spec
+	case *batchv1.CronJob:
+		return &obj.Spec.JobTemplate.Spec.Template.Spec
+	case *batchv1beta1.CronJob:
+		return &obj.Spec.JobTemplate.Spec.Template.Spec
+	}
+
+	return nil
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+
+	return nil
+}
+
+// NewFromWorkload creates a Result given a workload, using the containers from its pod spec
+// or only the configured container when one is set.
+func NewFromWorkload(client kubernetes.Interface, opt *options.Client, workload runtime.Object) *Result {
+	spec := PodSpec(workload)
+	if spec == nil {
+		return NewFromError(ErrUnsupportedWorkload)
+	}
+
+	containers := spec.Containers
+
+	if opt.Container != "" {
+		container := findContainer(containers, opt.Container)
+		if container == nil {
+			return NewFromError(fmt.Errorf("%w: %s", ErrMissingContainer, opt.Container))
+		}
+
+		containers = []corev1.Container{*container}
+	}
+
+	res := NewFromContainers(client, opt, containers)
+	if res.Error == nil {
+		res.Workload = workload
+		res.container = opt.Container
+	}
+
+	return res
+}
@@ -0,0 +1,94 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eiladin/k8s-dotenv/pkg/options"
+	"github.com/eiladin/k8s-dotenv/pkg/testing/mock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestPodSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload runtime.Object
+		wantNil  bool
+	}{
+		{name: "pod", workload: mock.Pod("test", "test", nil, nil, nil)},
+		{name: "deployment", workload: mock.Deployment("test", "test", nil, nil, nil)},
+		{name: "daemonset", workload: mock.DaemonSet("test", "test", nil, nil, nil)},
+		{name: "replicaset", workload: mock.ReplicaSet("test", "test", nil, nil, nil)},
+		{name: "statefulset", workload: mock.StatefulSet("test", "test", nil, nil, nil)},
+		{name: "job", workload: mock.Job("test", "test", nil, nil, nil)},
+		{name: "cronjob v1", workload: mock.CronJobv1("test", "test", nil, nil, nil)},
+		{name: "cronjob v1beta1", workload: mock.CronJobv1beta1("test", "test", nil, nil, nil)},
+		{name: "unsupported", workload: &corev1.Secret{}, wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PodSpec(tt.workload)
+			if (got == nil) != tt.wantNil {
+				t.Errorf("PodSpec() = %v, wantNil %v", got, tt.wantNil)
+
+				return
+			}
+
+			if got != nil && len(got.Containers) != 1 {
+				t.Errorf("PodSpec().Containers = %v, want 1 container", got.Containers)
+			}
+		})
+	}
+}
+
+func TestNewFromWorkload(t *testing.T) {
+	workload := mock.Deployment("test", "test", map[string]string{"k": "v"}, nil, nil)
+	kubeClient := mock.NewFakeClient()
+
+	tests := []struct {
+		name     string
+		workload runtime.Object
+		wantErr  error
+	}{
+		{name: "create", workload: workload},
+		{name: "error on unsupported workload", workload: &corev1.Secret{}, wantErr: ErrUnsupportedWorkload},
+		{
+			name:     "return container errors",
+			workload: mock.Deployment("test", "test", nil, []string{"missing"}, nil),
+			wantErr:  ErrMissingResource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewFromWorkload(kubeClient, &options.Client{Namespace: "test"}, tt.workload)
+			if !errors.Is(got.Error, tt.wantErr) {
+				t.Errorf("NewFromWorkload() error = %v, wantErr %v", got.Error, tt.wantErr)
+			}
+
+			if (got.Workload != nil) != (tt.wantErr == nil) {
+				t.Errorf("NewFromWorkload().Workload = %v", got.Workload)
+			}
+		})
+	}
+}
+
+func TestNewFromWorkload_container(t *testing.T) {
+	workload := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "api", Env: []corev1.EnvVar{{Name: "api", Value: "v"}}},
+		{Name: "sidecar", Env: []corev1.EnvVar{{Name: "sidecar", Value: "v"}}},
+	}}}
+	kubeClient := mock.NewFakeClient()
+
+	got := NewFromWorkload(kubeClient, &options.Client{Container: "api"}, workload)
+	if got.Error != nil || len(got.Environment) != 1 || got.Environment["api"] != "v" {
+		t.Errorf("NewFromWorkload() = %v, want only the api container", got.Environment)
+	}
+
+	got = NewFromWorkload(kubeClient, &options.Client{Container: "missing"}, workload)
+	if !errors.Is(got.Error, ErrMissingContainer) {
+		t.Errorf("NewFromWorkload() error = %v, wantErr %v", got.Error, ErrMissingContainer)
+	}
+}
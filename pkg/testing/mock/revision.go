@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func ownerReference(kind string, owner metav1.Object) []metav1.OwnerReference {
+	controller := true
+
+	return []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: kind, Name: owner.GetName(), UID: owner.GetUID(), Controller: &controller},
+	}
+}
+
+// DeploymentRevision returns a ReplicaSet owned by deployment holding a revision with env.
+func DeploymentRevision(deployment *appsv1.Deployment, revision int64, env map[string]string) *appsv1.ReplicaSet {
+	res := ReplicaSet(fmt.Sprintf("%s-%d", deployment.Name, revision), deployment.Namespace, env, nil, nil)
+	res.Annotations["deployment.kubernetes.io/revision"] = strconv.FormatInt(revision, 10)
+	res.OwnerReferences = ownerReference("Deployment", deployment)
+
+	return res
+}
+
+// ControllerRevision returns a ControllerRevision owned by a StatefulSet or DaemonSet holding a revision with env.
+func ControllerRevision(kind string, owner metav1.Object, revision int64, env map[string]string) *appsv1.ControllerRevision {
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{Container(env, nil, nil)}},
+			},
+		},
+	}
+
+	data, _ := json.Marshal(patch)
+
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%s-%d", strings.ToLower(kind), owner.GetName(), revision),
+			Namespace:       owner.GetNamespace(),
+			OwnerReferences: ownerReference(kind, owner),
+		},
+		Revision: revision,
+		Data:     runtime.RawExtension{Raw: data},
+	}
+}